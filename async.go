@@ -0,0 +1,223 @@
+package logbuch
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultQueueSize = 1024
+)
+
+// OverflowPolicy defines what happens when an entry is logged while the queue of an asynchronous Logger is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks until there is space in the queue.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the entry to be logged.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest entry in the queue to make space for the new one.
+	OverflowDropOldest
+
+	// OverflowDropBelowLevel drops entries below AsyncOptions.DropLevel and blocks for all other entries.
+	OverflowDropBelowLevel
+)
+
+// AsyncOptions configures the asynchronous mode of a Logger.
+type AsyncOptions struct {
+	// QueueSize is the maximum number of entries waiting to be written.
+	// Values below or equal to 0 will use the default of 1024 entries.
+	QueueSize int
+
+	// Policy is the OverflowPolicy used when the queue is full. Entries logged by Fatal are never dropped.
+	Policy OverflowPolicy
+
+	// DropLevel is the level below which entries are dropped when using OverflowDropBelowLevel.
+	DropLevel int
+
+	// ErrorHandler is called by the background goroutine for entries which could not be written,
+	// including panics recovered from the Formatter or outputs. Errors are ignored if it is nil.
+	// It must not log using the same Logger.
+	ErrorHandler func(error)
+}
+
+type asyncQueue struct {
+	entries      chan *Entry
+	policy       OverflowPolicy
+	dropLevel    int
+	dropped      *uint64
+	errorHandler func(error)
+	pending      int
+	cond         *sync.Cond
+	done         chan struct{}
+}
+
+// SetAsync enables the asynchronous mode.
+// Instead of writing entries immediately, they are put into a bounded queue and written by a background goroutine.
+// The Formatter is called by the background goroutine too, so the Params and Fields of an entry are read after the log function returned.
+// Don't modify them after passing them to the Logger.
+// Calling SetAsync while the asynchronous mode is enabled closes the current queue after writing all entries first.
+// Use Close to write all remaining entries and disable the asynchronous mode before shutting down.
+func (log *Logger) SetAsync(options AsyncOptions) {
+	log = log.root()
+	log.Close()
+
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultQueueSize
+	}
+
+	queue := &asyncQueue{entries: make(chan *Entry, options.QueueSize),
+		policy:       options.Policy,
+		dropLevel:    getValidLevel(options.DropLevel),
+		dropped:      &log.dropped,
+		errorHandler: options.ErrorHandler,
+		cond:         sync.NewCond(new(sync.Mutex)),
+		done:         make(chan struct{})}
+	log.asyncM.Lock()
+	log.async = queue
+	log.asyncM.Unlock()
+	go queue.run(log)
+}
+
+// Flush blocks until all entries queued in asynchronous mode have been written.
+// It does nothing if the asynchronous mode is disabled.
+func (log *Logger) Flush() {
+	log = log.root()
+	log.asyncM.RLock()
+	queue := log.async
+	log.asyncM.RUnlock()
+
+	if queue != nil {
+		queue.flush()
+	}
+}
+
+// Close writes all entries queued in asynchronous mode and disables it.
+// Entries logged afterwards are written synchronously.
+// It does nothing if the asynchronous mode is disabled.
+func (log *Logger) Close() {
+	log = log.root()
+	log.asyncM.Lock()
+	queue := log.async
+	log.async = nil
+	log.asyncM.Unlock()
+
+	if queue != nil {
+		close(queue.entries)
+		<-queue.done
+	}
+}
+
+// Dropped returns the total number of entries dropped in asynchronous mode because the queue was full.
+func (log *Logger) Dropped() uint64 {
+	return atomic.LoadUint64(&log.root().dropped)
+}
+
+func (queue *asyncQueue) enqueue(entry *Entry) {
+	queue.add(1)
+
+	// the entry logged by Fatal must be written before the program stops
+	if entry.Fatal {
+		queue.entries <- entry
+		return
+	}
+
+	switch queue.policy {
+	case OverflowDropNewest:
+		select {
+		case queue.entries <- entry:
+		default:
+			queue.drop()
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case queue.entries <- entry:
+				return
+			default:
+			}
+
+			select {
+			case oldest := <-queue.entries:
+				if oldest.Fatal {
+					// put it back, as it must not be dropped
+					queue.entries <- oldest
+				} else {
+					queue.drop()
+				}
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if entry.Level >= queue.dropLevel {
+			queue.entries <- entry
+			return
+		}
+
+		select {
+		case queue.entries <- entry:
+		default:
+			queue.drop()
+		}
+	default:
+		queue.entries <- entry
+	}
+}
+
+func (queue *asyncQueue) run(log *Logger) {
+	for entry := range queue.entries {
+		queue.write(log, entry)
+	}
+
+	close(queue.done)
+}
+
+func (queue *asyncQueue) write(log *Logger, entry *Entry) {
+	// the entry must be marked as done even if writing panics
+	defer queue.add(-1)
+
+	// a panic on the background goroutine would stop the program, as the caller cannot recover it
+	defer func() {
+		if r := recover(); r != nil {
+			queue.reportErr(fmt.Errorf("writing log entry panicked: %v", r))
+		}
+	}()
+
+	if err := log.writeEntry(entry); err != nil {
+		queue.reportErr(err)
+	}
+}
+
+func (queue *asyncQueue) reportErr(err error) {
+	if queue.errorHandler != nil {
+		queue.errorHandler(err)
+	}
+}
+
+func (queue *asyncQueue) drop() {
+	atomic.AddUint64(queue.dropped, 1)
+	queue.add(-1)
+}
+
+func (queue *asyncQueue) add(n int) {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+	queue.pending += n
+
+	if queue.pending == 0 {
+		queue.cond.Broadcast()
+	}
+}
+
+func (queue *asyncQueue) flush() {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	for queue.pending > 0 {
+		queue.cond.Wait()
+	}
+}
@@ -0,0 +1,208 @@
+package logbuch
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type blockingWriter struct {
+	m       sync.Mutex
+	buffer  bytes.Buffer
+	release chan struct{}
+}
+
+func (writer *blockingWriter) Write(p []byte) (int, error) {
+	<-writer.release
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	return writer.buffer.Write(p)
+}
+
+func (writer *blockingWriter) String() string {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	return writer.buffer.String()
+}
+
+func TestLoggerAsync(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewStandardFormatter(""))
+	logger.SetAsync(AsyncOptions{QueueSize: 4})
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			logger.With(Fields{"n": i}).Info("message")
+		}(i)
+	}
+
+	wg.Wait()
+	logger.Flush()
+
+	if strings.Count(buffer.String(), "[INFO ] message n=") != 10 {
+		t.Fatalf("All entries must have been written, but was: %v", buffer.String())
+	}
+
+	logger.Info("last")
+	logger.Close()
+
+	if !strings.HasSuffix(buffer.String(), "[INFO ] last\n") || logger.Dropped() != 0 {
+		t.Fatalf("Entries must have been written on close, but was: %v", buffer.String())
+	}
+
+	logger.Info("sync")
+
+	if !strings.HasSuffix(buffer.String(), "[INFO ] sync\n") {
+		t.Fatalf("Logger must write synchronously after close, but was: %v", buffer.String())
+	}
+}
+
+func TestLoggerAsyncOverflow(t *testing.T) {
+	input := []struct {
+		policy   OverflowPolicy
+		expected []string
+		dropped  uint64
+	}{
+		{OverflowDropNewest, []string{"first", "1", "2"}, 3},
+		{OverflowDropOldest, []string{"first", "4", "error"}, 3},
+		{OverflowDropBelowLevel, []string{"first", "1", "2", "error"}, 2},
+	}
+
+	for _, in := range input {
+		writer := &blockingWriter{release: make(chan struct{})}
+		logger := NewLogger(writer, writer)
+		logger.SetFormatter(NewStandardFormatter(""))
+		logger.SetAsync(AsyncOptions{QueueSize: 2, Policy: in.policy, DropLevel: LevelError})
+		release := func() {
+			for range in.expected {
+				writer.release <- struct{}{}
+			}
+		}
+
+		// the first entry is taken by the worker, which blocks until the writer is released
+		logger.Info("first")
+
+		for len(logger.async.entries) != 0 {
+			runtime.Gosched()
+		}
+
+		for i := 1; i < 5; i++ {
+			logger.Info(fmt.Sprint(i))
+		}
+
+		if in.policy == OverflowDropBelowLevel {
+			// blocks until there is space in the queue
+			go release()
+			logger.Error("error")
+		} else {
+			logger.Error("error")
+			go release()
+		}
+
+		logger.Close()
+		out := writer.String()
+
+		for _, exp := range in.expected {
+			if !strings.Contains(out, exp+"\n") {
+				t.Fatalf("Expected '%v' in '%v' for policy %v", exp, out, in.policy)
+			}
+		}
+
+		if logger.Dropped() != in.dropped || strings.Count(out, "\n") != len(in.expected) {
+			t.Fatalf("Expected %v entries to be dropped for policy %v, but was: %v %v", in.dropped, in.policy, logger.Dropped(), out)
+		}
+	}
+}
+
+func TestLoggerAsyncFatal(t *testing.T) {
+	var stderr bytes.Buffer
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Fatal must panic")
+		}
+
+		if !strings.Contains(stderr.String(), "Fatal message") {
+			t.Fatalf("Log must contain error message")
+		}
+	}()
+
+	logger := NewLogger(nil, &stderr)
+	logger.SetAsync(AsyncOptions{})
+	defer logger.Close()
+	logger.Fatal("Fatal %v", "message")
+}
+
+func TestLoggerAsyncFatalOverflow(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropNewest, OverflowDropOldest} {
+		writer := &blockingWriter{release: make(chan struct{})}
+		logger := NewLogger(writer, writer)
+		logger.SetFormatter(NewStandardFormatter(""))
+		logger.SetAsync(AsyncOptions{QueueSize: 1, Policy: policy})
+		logger.Info("first")
+
+		for len(logger.async.entries) != 0 {
+			runtime.Gosched()
+		}
+
+		logger.Info("queued")
+
+		go func() {
+			time.Sleep(time.Millisecond * 20)
+			close(writer.release)
+		}()
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatal("Fatal must panic")
+				}
+			}()
+
+			logger.Fatal("fatal")
+		}()
+
+		if !strings.HasSuffix(writer.String(), "[ERROR] fatal\n") {
+			t.Fatalf("Fatal entry must not be dropped for policy %v, but was: %q", policy, writer.String())
+		}
+
+		logger.Close()
+	}
+}
+
+func TestLoggerAsyncErrors(t *testing.T) {
+	var errs []error
+	logger := NewLogger(failingWriter{}, failingWriter{})
+	logger.PanicOnErr = true
+	logger.SetAsync(AsyncOptions{ErrorHandler: func(err error) {
+		errs = append(errs, err)
+	}})
+	logger.Info("failing")
+	logger.Flush()
+	logger.SetFormatter(panickingFormatter{})
+	logger.Info("panic")
+	logger.Close()
+
+	if len(errs) != 2 || errs[0].Error() != "write failed" || errs[1].Error() != "writing log entry panicked: format failed" {
+		t.Fatalf("Errors must be passed to the error handler, but was: %v", errs)
+	}
+}
+
+type panickingFormatter struct{}
+
+func (formatter panickingFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	panic("format failed")
+}
+
+func (formatter panickingFormatter) Pnc(msg string, params []interface{}) {
+	panic(msg)
+}
@@ -0,0 +1,55 @@
+package logbuch
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// Caller is the location in source code a log entry was created at.
+type Caller struct {
+	// File is the full path of the source file.
+	File string
+
+	// Line is the line number in the source file.
+	Line int
+
+	// Function is the fully qualified name of the function.
+	Function string
+}
+
+// String returns the file name and line number, like file.go:123.
+func (caller *Caller) String() string {
+	return filepath.Base(caller.File) + ":" + strconv.Itoa(caller.Line)
+}
+
+// SetReportCaller enables or disables adding the caller to log entries.
+// This is disabled by default, as capturing the caller is costly.
+// Entries written through the Writer or StdLogger don't have a caller.
+func (log *Logger) SetReportCaller(report bool) {
+	var value int32
+
+	if report {
+		value = 1
+	}
+
+	atomic.StoreInt32(&log.root().caller, value)
+}
+
+// GetReportCaller returns whether the caller is added to log entries.
+func (log *Logger) GetReportCaller() bool {
+	return atomic.LoadInt32(&log.root().caller) == 1
+}
+
+// callerAt returns the caller skipping given number of stack frames above the function calling callerAt.
+func callerAt(skip int) *Caller {
+	var pcs [1]uintptr
+
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return nil
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	return &Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+}
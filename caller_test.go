@@ -0,0 +1,125 @@
+package logbuch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoggerCaller(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.Info("disabled")
+
+	if out.String() != "[INFO ] disabled\n" {
+		t.Fatalf("Caller must be disabled by default, but was: %q", out.String())
+	}
+
+	l.SetReportCaller(true)
+	out.Reset()
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("info")
+	l.With(Fields{"key": "value"}).Warn("warn")
+	l.ErrorCtx(context.Background(), "error")
+	expected := fmt.Sprintf("[INFO ] caller_test.go:%d info\n[WARN ] caller_test.go:%d warn key=value\n[ERROR] caller_test.go:%d error\n", line+1, line+2, line+3)
+
+	if out.String() != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, out.String())
+	}
+
+	out.Reset()
+	defer func() {
+		recover()
+
+		if !strings.HasPrefix(out.String(), fmt.Sprintf("[ERROR] caller_test.go:%d fatal", line+1)) {
+			t.Fatalf("Unexpected output: %q", out.String())
+		}
+	}()
+	_, _, line, _ = runtime.Caller(0)
+	l.Fatal("fatal")
+}
+
+func TestLoggerCallerConcurrent(t *testing.T) {
+	l := NewLogger(io.Discard, io.Discard)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			l.Warn("warning")
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		l.SetReportCaller(i%2 == 0)
+	}
+
+	<-done
+}
+
+func TestFuncsCaller(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out, &out)
+	SetFormatter(NewStandardFormatter(""))
+	SetLevel(LevelDebug)
+	SetReportCaller(true)
+	defer SetReportCaller(false)
+	defer SetFormatter(NewStandardFormatter(StandardTimeFormat))
+	_, _, line, _ := runtime.Caller(0)
+	Debug("debug")
+	InfoCtx(context.Background(), "info")
+	expected := fmt.Sprintf("[DEBUG] caller_test.go:%d debug\n[INFO ] caller_test.go:%d info\n", line+1, line+2)
+
+	if out.String() != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, out.String())
+	}
+}
+
+func TestCallerStructuredFormatters(t *testing.T) {
+	entry := &Entry{Level: LevelInfo, Message: "msg", Caller: &Caller{File: "/src/app/main.go", Line: 42}}
+	var buffer []byte
+	NewJSONFormatter("").FmtEntry(&buffer, entry)
+
+	if string(buffer) != `{"level":"info","caller":"main.go:42","msg":"msg"}`+"\n" {
+		t.Fatalf("Unexpected JSON: %q", string(buffer))
+	}
+
+	buffer = buffer[:0]
+	NewLogfmtFormatter("").FmtEntry(&buffer, entry)
+
+	if string(buffer) != "level=info caller=main.go:42 msg=msg\n" {
+		t.Fatalf("Unexpected logfmt: %q", string(buffer))
+	}
+
+	buffer = buffer[:0]
+	NewFieldFormatter("", "\t").FmtEntry(&buffer, entry)
+
+	if string(buffer) != "[INFO ] main.go:42 msg\n" {
+		t.Fatalf("Unexpected field output: %q", string(buffer))
+	}
+}
+
+func BenchmarkLogger(b *testing.B) {
+	benchmarkLoggerCaller(b, false)
+}
+
+func BenchmarkLoggerCaller(b *testing.B) {
+	benchmarkLoggerCaller(b, true)
+}
+
+func benchmarkLoggerCaller(b *testing.B, caller bool) {
+	l := NewLogger(io.Discard, io.Discard)
+	l.SetReportCaller(caller)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Info("Benchmark message %d", i)
+	}
+}
@@ -0,0 +1,138 @@
+package logbuch
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// Compressor is an interface to compress rotated log files.
+// Implement it to use other algorithms than gzip, like zstd.
+type Compressor interface {
+	// Extension returns the file extension appended to compressed files, like ".gz".
+	Extension() string
+
+	// Compress reads the data from src and writes the compressed data to dst.
+	Compress(dst io.Writer, src io.Reader) error
+}
+
+// GzipCompressor compresses log files using gzip.
+type GzipCompressor struct {
+	level int
+}
+
+// NewGzipCompressor creates a new GzipCompressor with given compression level.
+// Invalid levels will use gzip.DefaultCompression.
+func NewGzipCompressor(level int) *GzipCompressor {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+
+	return &GzipCompressor{level: level}
+}
+
+// Extension returns ".gz".
+func (compressor *GzipCompressor) Extension() string {
+	return ".gz"
+}
+
+// Compress compresses src using gzip and writes the result to dst.
+func (compressor *GzipCompressor) Compress(dst io.Writer, src io.Reader) error {
+	w, err := gzip.NewWriterLevel(dst, compressor.level)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// CompressWith makes the RollingFileAppender compress log files after they have been rotated.
+// Compression is performed in the background and doesn't block writing.
+// Compressed files replace the original ones and are included in the file limit.
+// Errors are passed to the handler set with OnError.
+func CompressWith(compressor Compressor) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.compressor = compressor
+	}
+}
+
+// compress compresses the file at given path in the background.
+// It must be called while holding the lock.
+func (appender *RollingFileAppender) compress(path string) {
+	if appender.compressing == nil {
+		appender.compressing = make(map[string]*deletedFile)
+	}
+
+	appender.compressing[path] = nil
+	appender.tasks.Add(1)
+
+	go func() {
+		defer appender.tasks.Done()
+		compressedPath := path + appender.compressor.Extension()
+		err := compressFile(appender.compressor, path, compressedPath)
+		appender.m.Lock()
+		deleted := appender.compressing[path]
+		delete(appender.compressing, path)
+
+		// the file has been deleted in the meantime, so the compressed file is deleted for the same reason
+		if err == nil && deleted != nil {
+			err = appender.removeFile(compressedPath, deleted.reason)
+		}
+
+		// make the renamed and removed files durable
+		if err == nil {
+			err = appender.syncDir()
+		}
+
+		appender.unlock()
+
+		if err != nil {
+			appender.reportErr(err)
+		}
+	}()
+}
+
+func compressFile(compressor Compressor, path, compressedPath string) error {
+	src, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		// deleted in the meantime
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	defer src.Close()
+	tmpPath := compressedPath + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0664)
+
+	if err != nil {
+		return err
+	}
+
+	if err := compressor.Compress(dst, src); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, compressedPath); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package logbuch
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type failingCompressor struct{}
+
+func (compressor *failingCompressor) Extension() string {
+	return ".fail"
+}
+
+func (compressor *failingCompressor) Compress(dst io.Writer, src io.Reader) error {
+	return errors.New("compression failed")
+}
+
+type blockingCompressor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (compressor *blockingCompressor) Extension() string {
+	return ".gz"
+}
+
+func (compressor *blockingCompressor) Compress(dst io.Writer, src io.Reader) error {
+	compressor.started <- struct{}{}
+	<-compressor.release
+	_, err := io.Copy(dst, src)
+	return err
+}
+
+func TestRollingFileAppenderCompressWith(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(3, 5, 10, "out", &testNameSchema{}, CompressWith(NewGzipCompressor(-100)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := rfa.Write([]byte(fmt.Sprintf("%d234\n", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.ReadDir("out")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dir) != 3 || dir[0].Name() != "2_log.txt.gz" || dir[1].Name() != "3_log.txt.gz" || dir[2].Name() != "4_log.txt" {
+		t.Fatalf("Rotated files must have been compressed and old ones deleted, but was: %v", dir)
+	}
+
+	f, err := os.Open("out/3_log.txt.gz")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(r)
+
+	if err != nil || len(content) == 0 || !strings.HasSuffix(string(content), "234\n") {
+		t.Fatalf("Compressed file must contain log data, but was: %v %v", err, string(content))
+	}
+}
+
+func TestRollingFileAppenderCompressWithError(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	var m sync.Mutex
+	var errs []error
+	rfa, err := NewRollingFileAppender(3, 5, 10, "out", &testNameSchema{}, CompressWith(&failingCompressor{}), OnError(func(err error) {
+		m.Lock()
+		defer m.Unlock()
+		errs = append(errs, err)
+	}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte(fmt.Sprintf("%d234\n", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 1 || errs[0].Error() != "compression failed" {
+		t.Fatalf("Error must have been reported, but was: %v", errs)
+	}
+
+	if _, err := os.Stat("out/1_log.txt"); err != nil {
+		t.Fatalf("Original file must be kept, but was: %v", err)
+	}
+
+	if _, err := os.Stat("out/1_log.txt.fail.tmp"); !os.IsNotExist(err) {
+		t.Fatalf("Temporary file must have been removed, but was: %v", err)
+	}
+}
+
+func TestRollingFileAppenderCompressDeleted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Files being compressed cannot be deleted on Windows")
+	}
+
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	compressor := &blockingCompressor{make(chan struct{}, 10), make(chan struct{})}
+	handler := &testDeleteHandler{deleted: make(map[string]DeleteReason)}
+	rfa, err := NewRollingFileAppender(2, 5, 1, "out", &testNameSchema{}, CompressWith(compressor), OnDelete(handler.handle))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("1234\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first file is deleted while it's being compressed
+	<-compressor.started
+
+	if _, err := rfa.Write([]byte("1234\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if reason, ok := handler.get("1_log.txt"); !ok || reason != DeleteFileLimit {
+		t.Fatalf("File must have been deleted, but was: %v %v", reason, ok)
+	}
+
+	close(compressor.release)
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if reason, ok := handler.get("1_log.txt.gz"); !ok || reason != DeleteFileLimit {
+		t.Fatalf("Compressed file must have been passed to the delete handler, but was: %v %v", reason, ok)
+	}
+
+	if _, err := os.Stat("out/1_log.txt.gz"); !os.IsNotExist(err) {
+		t.Fatalf("Compressed file must have been deleted, but was: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package logbuch
+
+import (
+	"context"
+	"sync"
+)
+
+type loggerContextKey struct{}
+
+// ContextExtractor returns the fields for values stored in given context.
+// It's called for each log entry written using one of the *Ctx functions and must be safe for concurrent use.
+type ContextExtractor func(context.Context) Fields
+
+var (
+	contextExtractors  []ContextExtractor
+	contextExtractorsM sync.RWMutex
+)
+
+// RegisterContextExtractor adds a ContextExtractor to the registry.
+// Fields returned by all registered extractors are added to log entries written using one of the *Ctx functions.
+//
+// Example:
+//  logbuch.RegisterContextExtractor(func(ctx context.Context) logbuch.Fields {
+//      if id, ok := ctx.Value(requestIDKey).(string); ok {
+//          return logbuch.Fields{"request_id": id}
+//      }
+//
+//      return nil
+//  })
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorsM.Lock()
+	defer contextExtractorsM.Unlock()
+	contextExtractors = append(contextExtractors, extractor)
+}
+
+// ResetContextExtractors removes all registered context extractors.
+func ResetContextExtractors() {
+	contextExtractorsM.Lock()
+	defer contextExtractorsM.Unlock()
+	contextExtractors = nil
+}
+
+// ContextValueExtractor returns a ContextExtractor adding the value stored in the context for given key as a field called name.
+// Nothing is added if the context does not contain a value for the key.
+func ContextValueExtractor(key interface{}, name string) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		if value := ctx.Value(key); value != nil {
+			return Fields{name: value}
+		}
+
+		return nil
+	}
+}
+
+// NewContext returns a copy of the context carrying given logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the Logger stored in given context or the default logger if there is none.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerContextKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+
+	return logger
+}
+
+// contextFields returns the fields extracted from the context merged with given fields.
+// Given fields take precedence over extracted fields.
+func contextFields(ctx context.Context, fields Fields) Fields {
+	if ctx == nil {
+		return fields
+	}
+
+	contextExtractorsM.RLock()
+	defer contextExtractorsM.RUnlock()
+
+	if len(contextExtractors) == 0 {
+		return fields
+	}
+
+	var merged Fields
+
+	for _, extractor := range contextExtractors {
+		extracted := extractor(ctx)
+
+		if len(extracted) > 0 && merged == nil {
+			merged = make(Fields, len(fields)+len(extracted))
+		}
+
+		for k, v := range extracted {
+			merged[k] = v
+		}
+	}
+
+	if merged == nil {
+		return fields
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return merged
+}
@@ -0,0 +1,51 @@
+package logbuch
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type testContextKey string
+
+func TestLoggerCtx(t *testing.T) {
+	RegisterContextExtractor(ContextValueExtractor(testContextKey("request_id"), "request_id"))
+	RegisterContextExtractor(func(ctx context.Context) Fields {
+		if tenant, ok := ctx.Value(testContextKey("tenant")).(string); ok {
+			return Fields{"tenant": tenant}
+		}
+
+		return nil
+	})
+	defer ResetContextExtractors()
+	var buffer bytes.Buffer
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewStandardFormatter(""))
+	ctx := context.WithValue(context.Background(), testContextKey("request_id"), 42)
+	ctx = context.WithValue(ctx, testContextKey("tenant"), "emvi")
+	logger.DebugCtx(ctx, "Debug %s", "message")
+	logger.InfoCtx(context.Background(), "Info")
+	logger.With(Fields{"tenant": "bound"}).WarnCtx(ctx, "Warning")
+	logger.ErrorCtx(ctx, "Error")
+	expected := "[DEBUG] Debug message request_id=42 tenant=emvi\n" +
+		"[INFO ] Info\n" +
+		"[WARN ] Warning request_id=42 tenant=bound\n" +
+		"[ERROR] Error request_id=42 tenant=emvi\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, buffer.String())
+	}
+}
+
+func TestNewContextFromContext(t *testing.T) {
+	if FromContext(context.Background()) != logger {
+		t.Fatal("Default logger must be returned if context contains none")
+	}
+
+	l := NewLogger(nil, nil).With(Fields{"key": "value"})
+	ctx := NewContext(context.Background(), l)
+
+	if FromContext(ctx) != l {
+		t.Fatal("Logger must be returned from context")
+	}
+}
@@ -0,0 +1,99 @@
+package logbuch
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// SyncMode defines when the RollingFileAppender calls fsync to make sure log data has been persisted to disk.
+type SyncMode int
+
+const (
+	// SyncNone leaves persisting log data to the operating system.
+	SyncNone SyncMode = iota
+
+	// SyncOnFlush syncs the active log file each time the buffer is flushed.
+	SyncOnFlush
+
+	// SyncOnInterval syncs the active log file in the interval passed to Durability.
+	SyncOnInterval
+
+	// SyncOnRotation syncs log files when they are rotated or the appender is closed.
+	SyncOnRotation
+)
+
+// Durability sets the SyncMode of the RollingFileAppender. The interval is only used for SyncOnInterval.
+// For all modes but SyncNone, log files are synced before they are closed and the log directory is synced after
+// files have been created, compressed or deleted, including by background tasks,
+// so that a power loss cannot cause rotated log files to be missing.
+// By default, SyncNone is used.
+func Durability(mode SyncMode, interval time.Duration) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.syncMode = mode
+		appender.syncInterval = interval
+	}
+}
+
+// sync syncs the active log file if data has been written since the last sync.
+func (appender *RollingFileAppender) sync() error {
+	if appender.syncMode == SyncNone || !appender.unsynced {
+		return nil
+	}
+
+	if err := appender.currentFile.Sync(); err != nil {
+		return err
+	}
+
+	appender.unsynced = false
+	return nil
+}
+
+// syncDir syncs the log directory, so that created and deleted files are persisted.
+func (appender *RollingFileAppender) syncDir() error {
+	// directories cannot be synced on Windows
+	if appender.syncMode == SyncNone || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir := appender.fileDir
+
+	if dir == "" {
+		dir = "."
+	}
+
+	d, err := os.Open(dir)
+
+	if err != nil {
+		return err
+	}
+
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+
+	return d.Close()
+}
+
+// syncPeriodically syncs the active log file in the background until the appender is closed.
+func (appender *RollingFileAppender) syncPeriodically() {
+	defer appender.wg.Done()
+	ticker := time.NewTicker(appender.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-appender.done:
+			return
+		case <-ticker.C:
+			appender.m.Lock()
+			err := appender.sync()
+			appender.unlock()
+
+			if err != nil {
+				appender.reportErr(err)
+			}
+		}
+	}
+}
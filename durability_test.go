@@ -0,0 +1,145 @@
+package logbuch
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRollingFileAppenderDurability(t *testing.T) {
+	for _, mode := range []SyncMode{SyncNone, SyncOnFlush, SyncOnInterval, SyncOnRotation} {
+		if err := os.RemoveAll("out"); err != nil {
+			t.Fatal(err)
+		}
+
+		rfa, err := NewRollingFileAppender(2, 10, 5, "out", &testNameSchema{}, Durability(mode, time.Millisecond*10))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 5; i++ {
+			if _, err := rfa.Write([]byte("12345\n")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if mode == SyncOnFlush && rfa.unsynced {
+			t.Fatal("File must have been synced on flush")
+		}
+
+		time.Sleep(time.Millisecond * 30)
+
+		if err := rfa.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if mode != SyncNone && rfa.unsynced {
+			t.Fatalf("File must have been synced on close for mode %v", mode)
+		}
+
+		dir, err := os.ReadDir("out")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(dir) != 2 {
+			t.Fatalf("Two log files must exist, but was: %v", dir)
+		}
+	}
+}
+
+func TestRollingFileAppenderDurabilityBackgroundTasks(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	var m sync.Mutex
+	var errs []error
+	rfa, err := NewRollingFileAppender(10, 5, 1, "out", &testNameSchema{},
+		Durability(SyncOnRotation, 0),
+		CompressWith(NewGzipCompressor(-1)),
+		MaxAge(time.Millisecond*50),
+		OnError(func(err error) {
+			m.Lock()
+			defer m.Unlock()
+			errs = append(errs, err)
+		}),
+		func(appender *RollingFileAppender) {
+			appender.retentionCheck = time.Millisecond * 10
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the rotated file is compressed and deleted in background, syncing the directory each time
+	time.Sleep(time.Millisecond * 150)
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if len(errs) != 0 {
+		t.Fatalf("No errors must have been reported, but was: %v", errs)
+	}
+
+	for _, name := range []string{"out/1_log.txt", "out/1_log.txt.gz"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Fatalf("Rotated file must have been deleted in background, but was: %v", err)
+		}
+	}
+}
+
+func BenchmarkRollingFileAppenderSyncNone(b *testing.B) {
+	benchmarkRollingFileAppenderSync(b, SyncNone)
+}
+
+func BenchmarkRollingFileAppenderSyncOnFlush(b *testing.B) {
+	benchmarkRollingFileAppenderSync(b, SyncOnFlush)
+}
+
+func BenchmarkRollingFileAppenderSyncOnInterval(b *testing.B) {
+	benchmarkRollingFileAppenderSync(b, SyncOnInterval)
+}
+
+func BenchmarkRollingFileAppenderSyncOnRotation(b *testing.B) {
+	benchmarkRollingFileAppenderSync(b, SyncOnRotation)
+}
+
+func benchmarkRollingFileAppenderSync(b *testing.B, mode SyncMode) {
+	if err := os.RemoveAll("out"); err != nil {
+		b.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(5, 1024*1024, 1024*4, "out", &testNameSchema{}, Durability(mode, time.Millisecond*100))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	l := NewLogger(rfa, rfa)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Info("Benchmark message %d", i)
+	}
+
+	b.StopTimer()
+
+	if err := rfa.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
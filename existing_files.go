@@ -0,0 +1,109 @@
+package logbuch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MatchingNameSchema is an optional interface for NameSchemas able to recognize the log files they have created.
+// If the NameSchema passed to the RollingFileAppender implements it, existing log files are adopted on startup,
+// so that the file limit holds across restarts.
+type MatchingNameSchema interface {
+	NameSchema
+
+	// Match returns whether the file name (without directory) has been created by this schema.
+	// The returned sequence number is used to order the files, lower numbers being older.
+	// Files with the same sequence number are ordered by modification time.
+	Match(name string) (seq int64, ok bool)
+}
+
+type existingFile struct {
+	path    string
+	seq     int64
+	modTime time.Time
+}
+
+// adoptFiles adds the files in the log directory matching the NameSchema to the list of files, oldest first.
+// Compressed files are recognized by the extension of the Compressor.
+func (appender *RollingFileAppender) adoptFiles() error {
+	schema, ok := appender.fileName.(MatchingNameSchema)
+
+	if !ok {
+		return nil
+	}
+
+	dir := appender.fileDir
+
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return err
+	}
+
+	files := make([]existingFile, 0, len(entries))
+	index := make(map[string]int)
+
+	for _, entry := range entries {
+		// symbolic links, like the one to the active log file, are never adopted
+		if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		name := entry.Name()
+
+		if appender.compressor != nil {
+			name = strings.TrimSuffix(name, appender.compressor.Extension())
+		}
+
+		seq, ok := schema.Match(name)
+
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		file := existingFile{path: filepath.Join(appender.fileDir, name), seq: seq, modTime: info.ModTime()}
+
+		// the compressed and uncompressed version of a file might both exist if compression was interrupted
+		if i, ok := index[file.path]; ok {
+			if file.modTime.After(files[i].modTime) {
+				files[i].modTime = file.modTime
+			}
+
+			continue
+		}
+
+		index[file.path] = len(files)
+		files = append(files, file)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].seq != files[j].seq {
+			return files[i].seq < files[j].seq
+		}
+
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.Before(files[j].modTime)
+		}
+
+		return files[i].path < files[j].path
+	})
+
+	for _, file := range files {
+		appender.fileNames = append(appender.fileNames, file.path)
+	}
+
+	return nil
+}
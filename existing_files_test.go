@@ -0,0 +1,70 @@
+package logbuch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testMatchingNameSchema struct {
+	testNameSchema
+}
+
+func (schema *testMatchingNameSchema) Match(name string) (int64, bool) {
+	if !strings.HasSuffix(name, "_log.txt") {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseInt(strings.TrimSuffix(name, "_log.txt"), 10, 64)
+	return seq, err == nil
+}
+
+func TestRollingFileAppenderAdoptFiles(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"10_log.txt", "2_log.txt.gz", "3_log.txt", "9_log.txt", "unrelated.txt"} {
+		if err := os.WriteFile(filepath.Join("out", name), []byte(name), 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema := &testMatchingNameSchema{testNameSchema{counter: 10}}
+	rfa, err := NewRollingFileAppender(3, 5, 10, "out", schema, CompressWith(NewGzipCompressor(0)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.ReadDir("out")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(dir))
+
+	for _, entry := range dir {
+		names = append(names, entry.Name())
+	}
+
+	if fmt.Sprint(names) != "[10_log.txt 11_log.txt 9_log.txt unrelated.txt]" {
+		t.Fatalf("Oldest files must have been deleted, but was: %v", names)
+	}
+
+	if fmt.Sprint(rfa.fileNames) != fmt.Sprint([]string{filepath.Join("out", "9_log.txt"), filepath.Join("out", "10_log.txt"), filepath.Join("out", "11_log.txt")}) {
+		t.Fatalf("Existing files must have been adopted, but was: %v", rfa.fileNames)
+	}
+}
@@ -2,6 +2,7 @@ package logbuch
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -32,11 +33,17 @@ func NewFieldFormatter(timeFormat, separator string) *FieldFormatter {
 
 // Fmt formats the message as described for the FieldFormatter.
 func (formatter *FieldFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	formatter.FmtEntry(buffer, &Entry{Level: level, Time: t, Message: msg, Params: params})
+}
+
+// FmtEntry formats the entry as described for the FieldFormatter.
+// Fields bound to the logger are added to the key value pairs.
+func (formatter *FieldFormatter) FmtEntry(buffer *[]byte, entry *Entry) {
 	if !formatter.disableTime {
-		*buffer = append(*buffer, t.Format(formatter.timeFormat)+" "...)
+		*buffer = append(*buffer, entry.Time.Format(formatter.timeFormat)+" "...)
 	}
 
-	switch level {
+	switch entry.Level {
 	case LevelDebug:
 		*buffer = append(*buffer, "[DEBUG] "...)
 	case LevelInfo:
@@ -47,27 +54,27 @@ func (formatter *FieldFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg
 		*buffer = append(*buffer, "[ERROR] "...)
 	}
 
-	*buffer = append(*buffer, msg...)
+	if entry.Caller != nil {
+		*buffer = append(*buffer, entry.Caller.String()+" "...)
+	}
 
-	if len(params) > 0 {
-		fields, ok := params[0].(Fields)
+	*buffer = append(*buffer, entry.Message...)
+	fields, params := mergeFields(entry.Fields, entry.Params)
 
-		if len(params) == 1 && ok {
-			*buffer = append(*buffer, formatter.separator...)
+	if len(fields) > 0 || len(params) > 0 {
+		*buffer = append(*buffer, formatter.separator...)
 
-			for k, v := range fields {
-				*buffer = append(*buffer, fmt.Sprintf(" %s=%v", k, v)...)
-			}
-		} else {
-			*buffer = append(*buffer, formatter.separator...)
+		for _, k := range sortedKeys(fields) {
+			*buffer = append(*buffer, fmt.Sprintf(" %s=%v", k, fields[k])...)
+		}
 
-			for _, v := range params {
-				*buffer = append(*buffer, fmt.Sprintf(" %v", v)...)
-			}
+		for _, v := range params {
+			*buffer = append(*buffer, fmt.Sprintf(" %v", v)...)
 		}
 	}
 
 	*buffer = append(*buffer, '\n')
+	*buffer = append(*buffer, entry.Stack...)
 }
 
 // Pnc formats the given message and panics.
@@ -79,8 +86,8 @@ func (formatter *FieldFormatter) Pnc(msg string, params []interface{}) {
 			var builder strings.Builder
 			builder.WriteString(msg)
 
-			for k, v := range fields {
-				builder.WriteString(fmt.Sprintf(" %s=%v", k, v))
+			for _, k := range sortedKeys(fields) {
+				builder.WriteString(fmt.Sprintf(" %s=%v", k, fields[k]))
 			}
 
 			panic(builder.String())
@@ -97,3 +104,41 @@ func (formatter *FieldFormatter) Pnc(msg string, params []interface{}) {
 func (formatter *FieldFormatter) panicWithFmt(msg string, params []interface{}) {
 	panic(fmt.Sprintf(msg, params...))
 }
+
+// mergeFields merges the fields bound to a logger with the parameters of a log entry.
+// If the first and only parameter is of type Fields, it's merged into the result and no parameters are returned.
+// Parameters take precedence over bound fields.
+func mergeFields(bound Fields, params []interface{}) (Fields, []interface{}) {
+	if len(params) == 1 {
+		if fields, ok := params[0].(Fields); ok {
+			if len(bound) == 0 {
+				return fields, nil
+			}
+
+			merged := make(Fields, len(bound)+len(fields))
+
+			for k, v := range bound {
+				merged[k] = v
+			}
+
+			for k, v := range fields {
+				merged[k] = v
+			}
+
+			return merged, nil
+		}
+	}
+
+	return bound, params
+}
+
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
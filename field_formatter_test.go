@@ -91,6 +91,21 @@ func TestFieldFormatterPanicFmtFields(t *testing.T) {
 	formatter.Pnc("message", []interface{}{Fields{"variable": "value", "more": 123}})
 }
 
+func TestFieldFormatterStableOrder(t *testing.T) {
+	formatter := NewFieldFormatter("", "\t")
+	fields := Fields{"c": 3, "a": 1, "b": 2, "d": 4, "e": 5}
+	var buffer []byte
+
+	for i := 0; i < 20; i++ {
+		buffer = buffer[:0]
+		formatter.FmtEntry(&buffer, &Entry{Level: LevelInfo, Message: "message", Params: []interface{}{Fields{"c": 3, "a": 1}}, Fields: fields})
+
+		if string(buffer) != "[INFO ] message\t a=1 b=2 c=3 d=4 e=5\n" {
+			t.Fatalf("Unexpected log: %v", string(buffer))
+		}
+	}
+}
+
 func TestFieldFormatterDiableTime(t *testing.T) {
 	formatter := NewFieldFormatter("", "\t\t\t")
 	var buffer []byte
@@ -100,3 +115,24 @@ func TestFieldFormatterDiableTime(t *testing.T) {
 		t.Fatalf("Unexpected log: %v", string(buffer))
 	}
 }
+
+func TestFieldFormatterEntryFields(t *testing.T) {
+	formatter := NewFieldFormatter("", "\t")
+	var buffer []byte
+	formatter.FmtEntry(&buffer, &Entry{Level: LevelInfo,
+		Message: "message",
+		Params:  []interface{}{Fields{"key": "value"}},
+		Fields:  Fields{"bound": 1, "key": "overridden"}})
+	out := string(buffer)
+
+	if out != "[INFO ] message\t bound=1 key=value\n" {
+		t.Fatalf("Unexpected log: %v", out)
+	}
+
+	buffer = buffer[:0]
+	formatter.FmtEntry(&buffer, &Entry{Level: LevelInfo, Message: "message", Params: []interface{}{"param"}, Fields: Fields{"bound": 1}})
+
+	if string(buffer) != "[INFO ] message\t bound=1 param\n" {
+		t.Fatalf("Unexpected log: %v", string(buffer))
+	}
+}
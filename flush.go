@@ -0,0 +1,49 @@
+package logbuch
+
+import (
+	"time"
+)
+
+// FlushEvery makes the RollingFileAppender flush its buffer in given interval,
+// so that log data doesn't stay in memory for long when there is little traffic.
+func FlushEvery(interval time.Duration) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.flushInterval = interval
+	}
+}
+
+// FlushOnLevel makes the RollingFileAppender flush its buffer immediately when a log entry of given level or above is written.
+// This only works if the RollingFileAppender is used as an output of a Logger, as the level is passed by the Logger.
+func FlushOnLevel(level int) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.flushLevel = getValidLevel(level)
+		appender.flushOnLevel = true
+	}
+}
+
+// flushPeriodically flushes the buffer in the background until the appender is closed.
+func (appender *RollingFileAppender) flushPeriodically() {
+	defer appender.wg.Done()
+	ticker := time.NewTicker(appender.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-appender.done:
+			return
+		case <-ticker.C:
+			var err error
+			appender.m.Lock()
+
+			if len(appender.buffer) > 0 {
+				err = appender.flush()
+			}
+
+			appender.unlock()
+
+			if err != nil {
+				appender.reportErr(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package logbuch
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRollingFileAppenderFlushEvery(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(2, 1000, 1000, "out", &testNameSchema{}, FlushEvery(time.Millisecond*10))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rfa.Write([]byte("12345\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	content, err := os.ReadFile("out/1_log.txt")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "12345\n" {
+		t.Fatalf("Buffer must have been flushed in background, but was: %v", string(content))
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRollingFileAppenderFlushOnLevel(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(2, 1000, 1000, "out", &testNameSchema{}, FlushOnLevel(LevelError))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(rfa, rfa)
+	l.SetFormatter(NewFieldFormatter("", "\t"))
+	l.Warn("warning")
+	content, err := os.ReadFile("out/1_log.txt")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(content) != 0 {
+		t.Fatalf("Warning must not have been flushed, but was: %v", string(content))
+	}
+
+	l.Error("error")
+	content, err = os.ReadFile("out/1_log.txt")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "[WARN ] warning\n[ERROR] error\n" {
+		t.Fatalf("Error must have been flushed immediately, but was: %v", string(content))
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package logbuch
 
 import (
+	"reflect"
 	"time"
 )
 
@@ -12,3 +13,59 @@ type Formatter interface {
 	// Pnc formats the given message and panics.
 	Pnc(string, []interface{})
 }
+
+// EntryFormatter is an optional interface for formatters that render the whole log Entry.
+// If the Formatter of a Logger implements it, FmtEntry is called instead of Fmt.
+// This is required to render fields bound to a Logger using With.
+type EntryFormatter interface {
+	Formatter
+
+	// FmtEntry formats a log entry and writes the result into the buffer.
+	FmtEntry(*[]byte, *Entry)
+}
+
+// Entry is a single log entry.
+type Entry struct {
+	// Level is the log level.
+	Level int
+
+	// Time is the time the entry was logged at.
+	Time time.Time
+
+	// Message is the unformatted message.
+	Message string
+
+	// Params are the parameters passed to the log function.
+	Params []interface{}
+
+	// Fields are the fields bound to the Logger.
+	Fields Fields
+
+	// Caller is the location the entry was logged at. It's nil unless enabled using SetReportCaller.
+	Caller *Caller
+
+	// Stack is the stack trace of the entry or the error passed to it. It's empty unless enabled using EnableStackTrace.
+	Stack string
+
+	// Fatal is set for entries logged by Fatal, which stops the program after the entry has been written.
+	Fatal bool
+}
+
+func levelName(level int) string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// isNilPointer returns whether given value is a nil pointer, like a typed nil error, which might panic when calling its methods.
+func isNilPointer(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -1,7 +1,9 @@
 package logbuch
 
 import (
+	"context"
 	"io"
+	"log"
 	"os"
 )
 
@@ -29,28 +31,107 @@ func SetFormatter(formatter Formatter) {
 	logger.SetFormatter(formatter)
 }
 
+// AddSink adds sinks the default logger writes to in addition to its outputs.
+func AddSink(sinks ...*Sink) {
+	logger.AddSink(sinks...)
+}
+
+// RemoveSink removes a sink from the default logger.
+func RemoveSink(sink *Sink) {
+	logger.RemoveSink(sink)
+}
+
+// SetReportCaller enables or disables adding the caller to log entries of the default logger.
+func SetReportCaller(report bool) {
+	logger.SetReportCaller(report)
+}
+
+// EnableStackTrace adds a stack trace to entries of given level and above logged by the default logger.
+func EnableStackTrace(level int) {
+	logger.EnableStackTrace(level)
+}
+
+// DisableStackTrace disables adding stack traces to entries of the default logger.
+func DisableStackTrace() {
+	logger.DisableStackTrace()
+}
+
+// SetSampling sets the SamplingPolicy for given level of the default logger.
+func SetSampling(level int, policy *SamplingPolicy) {
+	logger.SetSampling(level, policy)
+}
+
+// AddHook adds a hook for given levels to the default logger.
+func AddHook(hook *Hook, levels ...int) {
+	logger.AddHook(hook, levels...)
+}
+
+// RemoveHook removes a hook from the default logger.
+func RemoveHook(hook *Hook) {
+	logger.RemoveHook(hook)
+}
+
 // Debug logs a formatted debug message.
 func Debug(msg string, params ...interface{}) {
-	logger.Debug(msg, params...)
+	logger.logLevel(1, LevelDebug, nil, msg, params)
 }
 
 // Info logs a formatted info message.
 func Info(msg string, params ...interface{}) {
-	logger.Info(msg, params...)
+	logger.logLevel(1, LevelInfo, nil, msg, params)
 }
 
 // Warn logs a formatted warning message.
 func Warn(msg string, params ...interface{}) {
-	logger.Warn(msg, params...)
+	logger.logLevel(1, LevelWarning, nil, msg, params)
 }
 
 // Error logs a formatted error message.
 func Error(msg string, params ...interface{}) {
-	// maximum level cannot be disabled
-	logger.Error(msg, params...)
+	logger.logLevel(1, LevelError, nil, msg, params)
+}
+
+// DebugCtx logs a formatted debug message using the logger stored in the context or the default logger.
+// Fields extracted from the context are added to the entry.
+func DebugCtx(ctx context.Context, msg string, params ...interface{}) {
+	FromContext(ctx).logLevel(1, LevelDebug, ctx, msg, params)
+}
+
+// InfoCtx logs a formatted info message using the logger stored in the context or the default logger.
+// Fields extracted from the context are added to the entry.
+func InfoCtx(ctx context.Context, msg string, params ...interface{}) {
+	FromContext(ctx).logLevel(1, LevelInfo, ctx, msg, params)
+}
+
+// WarnCtx logs a formatted warning message using the logger stored in the context or the default logger.
+// Fields extracted from the context are added to the entry.
+func WarnCtx(ctx context.Context, msg string, params ...interface{}) {
+	FromContext(ctx).logLevel(1, LevelWarning, ctx, msg, params)
+}
+
+// ErrorCtx logs a formatted error message using the logger stored in the context or the default logger.
+// Fields extracted from the context are added to the entry.
+func ErrorCtx(ctx context.Context, msg string, params ...interface{}) {
+	FromContext(ctx).logLevel(1, LevelError, ctx, msg, params)
 }
 
 // Fatal logs a formatted error message and panics.
 func Fatal(msg string, params ...interface{}) {
-	logger.Fatal(msg, params...)
+	logger.fatal(1, msg, params)
+}
+
+// RedirectStdLog redirects the output of the standard library log package to the default logger using given level.
+// The prefix and flags of the standard logger are reset, as the formatter of the logger adds the timestamp.
+// The returned function restores the previous output, prefix and flags.
+func RedirectStdLog(level int) func() {
+	out, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	log.SetOutput(logger.Writer(level))
+	log.SetPrefix("")
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}
 }
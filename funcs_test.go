@@ -2,6 +2,7 @@ package logbuch
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 )
@@ -57,3 +58,32 @@ func TestSetFormatter(t *testing.T) {
 		t.Fatal("Formatter must have been set")
 	}
 }
+
+func TestFuncsCtx(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	SetOutput(&stdout, &stderr)
+	SetLevel(LevelDebug)
+	SetFormatter(NewStandardFormatter(""))
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	ctx := NewContext(context.Background(), l.With(Fields{"key": "value"}))
+	DebugCtx(ctx, "Debug %s", "message")
+	InfoCtx(ctx, "Info")
+	WarnCtx(ctx, "Warning")
+	ErrorCtx(ctx, "Error")
+	ErrorCtx(context.Background(), "Default")
+	expected := "[DEBUG] Debug message key=value\n" +
+		"[INFO ] Info key=value\n" +
+		"[WARN ] Warning key=value\n" +
+		"[ERROR] Error key=value\n"
+
+	if out.String() != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, out.String())
+	}
+
+	if stdout.String() != "" || stderr.String() != "[ERROR] Default\n" {
+		t.Fatalf("Unexpected default logger output: %v %v", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,117 @@
+package logbuch
+
+import (
+	"fmt"
+)
+
+// Hook processes log entries of the levels it has been added for, for example to count errors or copy entries to another store.
+// Hooks are called by the goroutine writing the entry while the Logger is locked, so they must not log using the same Logger.
+// Entries logged by Fatal are passed to hooks of LevelError before the program stops, with Entry.Fatal set.
+type Hook struct {
+	// Before is called before the entry is formatted. It can modify the entry or return false to drop it.
+	// The Fields of the entry are shared with the Logger, so assign a new Fields map instead of modifying them.
+	Before func(entry *Entry) (bool, error)
+
+	// After is called with the output of the Logger's Formatter before it is written. Return false to drop the entry.
+	// The data must not be modified or retained after returning. Sinks format the entry themselves after this hook.
+	After func(entry *Entry, data []byte) (bool, error)
+
+	// ErrorHandler is called for errors returned by Before and After and for panics recovered from them.
+	// Errors are ignored if it is nil. The entry is written unless the hook returned false.
+	ErrorHandler func(error)
+}
+
+// AddHook adds a hook for given levels. If no level is passed, the hook is called for all levels.
+//
+//  logbuch.AddHook(&logbuch.Hook{
+//      Before: func(entry *logbuch.Entry) (bool, error) {
+//          errorCounter.Inc()
+//          return true, nil
+//      },
+//  }, logbuch.LevelError)
+func (log *Logger) AddHook(hook *Hook, levels ...int) {
+	log = log.root()
+	log.m.Lock()
+	defer log.m.Unlock()
+
+	if len(levels) == 0 {
+		levels = []int{LevelDebug, LevelInfo, LevelWarning, LevelError}
+	}
+
+	for _, level := range levels {
+		level = getValidLevel(level)
+		log.hooks[level] = append(log.hooks[level], hook)
+	}
+}
+
+// RemoveHook removes a hook added using AddHook for all levels.
+func (log *Logger) RemoveHook(hook *Hook) {
+	log = log.root()
+	log.m.Lock()
+	defer log.m.Unlock()
+
+	for level, hooks := range log.hooks {
+		for i, h := range hooks {
+			if h == hook {
+				log.hooks[level] = append(hooks[:i], hooks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// fireBefore calls the Before function of all hooks and returns false if one of them dropped the entry.
+func fireBefore(hooks []*Hook, entry *Entry) bool {
+	for _, hook := range hooks {
+		if hook.Before != nil && !hook.fire(func() (bool, error) {
+			return hook.Before(entry)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fireAfter calls the After function of all hooks and returns false if one of them dropped the entry.
+func fireAfter(hooks []*Hook, entry *Entry, data []byte) bool {
+	for _, hook := range hooks {
+		if hook.After != nil && !hook.fire(func() (bool, error) {
+			return hook.After(entry, data)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fire calls given function, passing errors and recovered panics to the error handler.
+func (hook *Hook) fire(f func() (bool, error)) (keep bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			keep = true
+			hook.reportErr(fmt.Errorf("hook panicked: %v", r))
+		}
+	}()
+
+	keep, err := f()
+
+	if err != nil {
+		hook.reportErr(err)
+	}
+
+	return
+}
+
+func (hook *Hook) reportErr(err error) {
+	if hook.ErrorHandler == nil {
+		return
+	}
+
+	// a panicking error handler must not take the logger down either
+	defer func() {
+		recover()
+	}()
+	hook.ErrorHandler(err)
+}
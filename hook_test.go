@@ -0,0 +1,197 @@
+package logbuch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestLoggerHook(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewFieldFormatter("", "\t"))
+	var formatted []string
+	hook := &Hook{
+		Before: func(entry *Entry) (bool, error) {
+			if entry.Message == "veto" {
+				return false, nil
+			}
+
+			entry.Fields = Fields{"hook": true}
+			return true, nil
+		},
+		After: func(entry *Entry, data []byte) (bool, error) {
+			formatted = append(formatted, string(data))
+			return entry.Message != "veto after", nil
+		},
+	}
+	l.AddHook(hook, LevelWarning, LevelError)
+	l.Info("info")
+	l.Warn("warning")
+	l.Error("veto")
+	l.Error("veto after")
+
+	if out.String() != "[INFO ] info\n[WARN ] warning\t hook=true\n" {
+		t.Fatalf("Hooks must be called for configured levels, but was: %q", out.String())
+	}
+
+	if len(formatted) != 2 || formatted[0] != "[WARN ] warning\t hook=true\n" || formatted[1] != "[ERROR] veto after\t hook=true\n" {
+		t.Fatalf("After hook must be called with formatted entries, but was: %q", formatted)
+	}
+
+	l.RemoveHook(hook)
+	out.Reset()
+	l.Error("veto")
+
+	if out.String() != "[ERROR] veto\n" {
+		t.Fatalf("Hook must be removed, but was: %q", out.String())
+	}
+}
+
+func TestLoggerHookAllLevels(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetLevel(LevelDebug)
+	var levels []int
+	l.With(Fields{"key": "value"}).AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			levels = append(levels, entry.Level)
+			return true, nil
+		},
+	})
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warning")
+	l.Error("error")
+
+	if len(levels) != 4 || levels[0] != LevelDebug || levels[3] != LevelError {
+		t.Fatalf("Hook must be called for all levels, but was: %v", levels)
+	}
+}
+
+func TestLoggerHookFatal(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	var fatal []bool
+	l.AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			fatal = append(fatal, entry.Fatal)
+			return true, nil
+		},
+	}, LevelError)
+	l.Error("error")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Fatal must panic")
+		}
+
+		if len(fatal) != 2 || fatal[0] || !fatal[1] {
+			t.Fatalf("Entries logged by Fatal must be marked, but was: %v", fatal)
+		}
+	}()
+
+	l.Fatal("fatal")
+}
+
+func TestLoggerHookSinks(t *testing.T) {
+	var out, sinkOut bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.AddSink(NewSink(&sinkOut, NewStandardFormatter(""), LevelDebug))
+	l.AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			entry.Message = "modified"
+			return true, nil
+		},
+		After: func(entry *Entry, data []byte) (bool, error) {
+			return entry.Level != LevelError, nil
+		},
+	})
+	l.Info("info")
+	l.Error("error")
+
+	if out.String() != "[INFO ] modified\n" || sinkOut.String() != "[INFO ] modified\n" {
+		t.Fatalf("Hooks must apply to sinks, but was: %q %q", out.String(), sinkOut.String())
+	}
+}
+
+func TestLoggerHookErrors(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	var errs []error
+	l.AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			if entry.Message == "panic" {
+				panic("hook failed")
+			}
+
+			return true, errors.New("hook error")
+		},
+		After: func(entry *Entry, data []byte) (bool, error) {
+			var m map[string]int
+			m["nil"]++
+			return false, nil
+		},
+		ErrorHandler: func(err error) {
+			errs = append(errs, err)
+		},
+	})
+	l.AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			panic("no error handler")
+		},
+	})
+	l.Info("error")
+	l.Info("panic")
+
+	if out.String() != "[INFO ] error\n[INFO ] panic\n" {
+		t.Fatalf("Entries must be written if hooks fail, but was: %q", out.String())
+	}
+
+	if len(errs) != 4 ||
+		errs[0].Error() != "hook error" ||
+		errs[1].Error() != "hook panicked: assignment to entry in nil map" ||
+		errs[2].Error() != "hook panicked: hook failed" {
+		t.Fatalf("Errors must be passed to the error handler, but was: %v", errs)
+	}
+}
+
+func TestLoggerHookPanickingErrorHandler(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.AddHook(&Hook{
+		Before: func(entry *Entry) (bool, error) {
+			return true, errors.New("hook error")
+		},
+		ErrorHandler: func(err error) {
+			panic(err)
+		},
+	})
+	l.Info("info")
+
+	if out.String() != "[INFO ] info\n" {
+		t.Fatalf("Entry must be written, but was: %q", out.String())
+	}
+}
+
+func TestFuncsHook(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out, &out)
+	SetFormatter(NewStandardFormatter(""))
+	hook := &Hook{
+		Before: func(entry *Entry) (bool, error) {
+			return false, nil
+		},
+	}
+	AddHook(hook, LevelInfo)
+	Info("dropped")
+	RemoveHook(hook)
+	Info("info")
+
+	if out.String() != "[INFO ] info\n" {
+		t.Fatalf("Hook must be added to the default logger, but was: %q", out.String())
+	}
+}
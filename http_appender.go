@@ -0,0 +1,415 @@
+package logbuch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHTTPBatchSize  = 100
+	defaultHTTPBatchBytes = 1024 * 1024 // 1 MB
+	defaultHTTPMaxLatency = time.Second
+	defaultHTTPQueueSize  = 10
+	defaultHTTPRetries    = 5
+	defaultHTTPMinBackoff = time.Millisecond * 500
+	defaultHTTPMaxBackoff = time.Second * 30
+	defaultHTTPTimeout    = time.Second * 10
+)
+
+// HTTPAppenderOptions configures the HTTPAppender.
+type HTTPAppenderOptions struct {
+	// Client is the http.Client used to send requests. A client with a timeout of ten seconds is used by default.
+	Client *http.Client
+
+	// Header is added to each request, for example to set an authorization header.
+	Header http.Header
+
+	// ContentType is the content type of the requests. "application/x-ndjson" is used by default.
+	ContentType string
+
+	// BatchSize is the maximum number of entries per batch. 100 are used by default.
+	BatchSize int
+
+	// BatchBytes is the maximum size of a batch in bytes before compression. One MB is used by default.
+	BatchBytes int
+
+	// MaxLatency is the maximum time an entry is held back before its batch is sent. One second is used by default.
+	MaxLatency time.Duration
+
+	// QueueSize is the number of batches waiting to be sent. If the queue is full, new batches are dropped.
+	// Ten are used by default.
+	QueueSize int
+
+	// MaxRetries is the number of times a batch is sent again on timeouts, server errors (5xx) and status 429.
+	// Five are used by default. Set it to a negative value to disable retries.
+	MaxRetries int
+
+	// MinBackoff is the time to wait before the first retry. It's doubled for each retry up to MaxBackoff,
+	// unless the server sets the Retry-After header, which is limited to MaxBackoff too.
+	// By default, the backoff ranges from 500 milliseconds to 30 seconds.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// DisableCompression disables compressing batches using gzip.
+	DisableCompression bool
+
+	// ErrorHandler is called for batches which could not be sent.
+	// It must be safe for concurrent use and must not write to the appender.
+	ErrorHandler func(error)
+}
+
+// HTTPAppender is an io.Writer sending log entries in batches to an HTTP endpoint using POST requests.
+// Each call to Write is an entry, so it works best with formatters writing one line per entry, like the JSONFormatter.
+// Batches are sent in the background when they are full or the maximum latency has been reached.
+// It needs to be closed using the Close() method.
+type HTTPAppender struct {
+	dropped   uint64 // must be the first field for 64-bit alignment of atomic operations
+	url       string
+	options   HTTPAppenderOptions
+	batch     []byte
+	entries   int
+	batchID   uint64
+	queue     chan httpBatch
+	pending   int
+	cond      *sync.Cond
+	closed    bool
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	m         sync.Mutex
+}
+
+type httpBatch struct {
+	data    []byte
+	entries int
+}
+
+// HTTPStatusError is passed to the error handler if the endpoint responds with an unexpected status code.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (err *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status code %d", err.StatusCode)
+}
+
+// NewHTTPAppender creates a new HTTPAppender sending entries to given URL.
+//
+//	appender := logbuch.NewHTTPAppender("https://logs.example.com/ingest", logbuch.HTTPAppenderOptions{})
+//	logbuch.SetFormatter(logbuch.NewJSONFormatter(time.RFC3339))
+//	logbuch.SetOutput(appender, appender)
+func NewHTTPAppender(url string, options HTTPAppenderOptions) *HTTPAppender {
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	if options.ContentType == "" {
+		options.ContentType = "application/x-ndjson"
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultHTTPBatchSize
+	}
+
+	if options.BatchBytes <= 0 {
+		options.BatchBytes = defaultHTTPBatchBytes
+	}
+
+	if options.MaxLatency <= 0 {
+		options.MaxLatency = defaultHTTPMaxLatency
+	}
+
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultHTTPQueueSize
+	}
+
+	if options.MaxRetries == 0 {
+		options.MaxRetries = defaultHTTPRetries
+	}
+
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaultHTTPMinBackoff
+	}
+
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = defaultHTTPMaxBackoff
+
+		if options.MaxBackoff < options.MinBackoff {
+			options.MaxBackoff = options.MinBackoff
+		}
+	}
+
+	appender := &HTTPAppender{url: url,
+		options: options,
+		queue:   make(chan httpBatch, options.QueueSize),
+		done:    make(chan struct{})}
+	appender.cond = sync.NewCond(&appender.m)
+	appender.wg.Add(1)
+	go appender.send()
+	return appender
+}
+
+// Write adds given entry to the current batch. A newline is appended if missing.
+func (appender *HTTPAppender) Write(p []byte) (int, error) {
+	appender.m.Lock()
+	defer appender.m.Unlock()
+
+	if appender.entries > 0 && len(appender.batch)+len(p)+1 > appender.options.BatchBytes {
+		appender.enqueue()
+	}
+
+	if appender.entries == 0 {
+		id := appender.batchID
+		time.AfterFunc(appender.options.MaxLatency, func() {
+			appender.m.Lock()
+			defer appender.m.Unlock()
+
+			// the batch might have been sent already
+			if appender.batchID == id && appender.entries > 0 {
+				appender.enqueue()
+			}
+		})
+	}
+
+	appender.batch = append(appender.batch, p...)
+
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		appender.batch = append(appender.batch, '\n')
+	}
+
+	appender.entries++
+
+	if appender.entries >= appender.options.BatchSize || len(appender.batch) >= appender.options.BatchBytes {
+		appender.enqueue()
+	}
+
+	return len(p), nil
+}
+
+// Flush sends the current batch and waits for all queued batches to be sent.
+func (appender *HTTPAppender) Flush() {
+	appender.m.Lock()
+	defer appender.m.Unlock()
+
+	if appender.entries > 0 {
+		appender.enqueue()
+	}
+
+	for appender.pending > 0 {
+		appender.cond.Wait()
+	}
+}
+
+// Close sends the remaining entries and stops sending in the background.
+// Batches failing on close are not retried, so that shutting down isn't delayed. Entries written afterwards are dropped.
+// Errors are passed to the error handler, so it always returns nil.
+func (appender *HTTPAppender) Close() error {
+	appender.closeOnce.Do(func() {
+		appender.m.Lock()
+
+		if appender.entries > 0 {
+			appender.enqueue()
+		}
+
+		appender.closed = true
+		appender.m.Unlock()
+		close(appender.done)
+	})
+	appender.wg.Wait()
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the queue was full or the batch could not be sent.
+func (appender *HTTPAppender) Dropped() uint64 {
+	return atomic.LoadUint64(&appender.dropped)
+}
+
+// enqueue adds the current batch to the queue and starts a new one.
+func (appender *HTTPAppender) enqueue() {
+	batch := httpBatch{appender.batch, appender.entries}
+	appender.batch = nil
+	appender.entries = 0
+	appender.batchID++
+
+	if appender.closed {
+		atomic.AddUint64(&appender.dropped, uint64(batch.entries))
+		return
+	}
+
+	select {
+	case appender.queue <- batch:
+		appender.pending++
+	default:
+		atomic.AddUint64(&appender.dropped, uint64(batch.entries))
+	}
+}
+
+func (appender *HTTPAppender) send() {
+	defer appender.wg.Done()
+
+	for {
+		select {
+		case <-appender.done:
+			// send the remaining batches, which are not retried anymore
+			for {
+				select {
+				case batch := <-appender.queue:
+					appender.process(batch)
+				default:
+					return
+				}
+			}
+		case batch := <-appender.queue:
+			appender.process(batch)
+		}
+	}
+}
+
+func (appender *HTTPAppender) process(batch httpBatch) {
+	if err := appender.sendBatch(batch); err != nil {
+		atomic.AddUint64(&appender.dropped, uint64(batch.entries))
+
+		if appender.options.ErrorHandler != nil {
+			appender.options.ErrorHandler(err)
+		}
+	}
+
+	appender.m.Lock()
+	defer appender.m.Unlock()
+	appender.pending--
+
+	if appender.pending == 0 {
+		appender.cond.Broadcast()
+	}
+}
+
+// sendBatch sends a batch and retries on timeouts, server errors and status 429 until the appender is closed.
+func (appender *HTTPAppender) sendBatch(batch httpBatch) error {
+	body, err := appender.compress(batch.data)
+
+	if err != nil {
+		return err
+	}
+
+	backoff := appender.options.MinBackoff
+
+	for i := 0; ; i++ {
+		retryAfter, err := appender.post(body)
+
+		if err == nil {
+			return nil
+		}
+
+		if retryAfter < 0 || i >= appender.options.MaxRetries {
+			return err
+		}
+
+		if retryAfter == 0 {
+			retryAfter = backoff
+			backoff *= 2
+
+			if backoff > appender.options.MaxBackoff {
+				backoff = appender.options.MaxBackoff
+			}
+		} else if retryAfter > appender.options.MaxBackoff {
+			retryAfter = appender.options.MaxBackoff
+		}
+
+		timer := time.NewTimer(retryAfter)
+
+		select {
+		case <-timer.C:
+		case <-appender.done:
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// post sends given body. It returns the time to wait before retrying,
+// which is 0 to use the backoff and negative if the request must not be retried.
+func (appender *HTTPAppender) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, appender.url, bytes.NewReader(body))
+
+	if err != nil {
+		return -1, err
+	}
+
+	for key, values := range appender.options.Header {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", appender.options.ContentType)
+
+	if !appender.options.DisableCompression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := appender.options.Client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// drain the body, so that the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = &HTTPStatusError{resp.StatusCode}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+
+	return parseRetryAfter(resp.Header.Get("Retry-After")), err
+}
+
+func (appender *HTTPAppender) compress(data []byte) ([]byte, error) {
+	if appender.options.DisableCompression {
+		return data, nil
+	}
+
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// parseRetryAfter returns the duration of the Retry-After header, which is either in seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,259 @@
+package logbuch
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testHTTPServer struct {
+	server   *httptest.Server
+	requests []string
+	status   []int
+	m        sync.Mutex
+}
+
+func newTestHTTPServer(t *testing.T, status ...int) *testHTTPServer {
+	s := &testHTTPServer{status: status}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.m.Lock()
+		defer s.m.Unlock()
+
+		if r.Header.Get("Content-Type") != "application/x-ndjson" || r.Header.Get("Authorization") != "token" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+
+		body := r.Body
+
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, err := gzip.NewReader(r.Body)
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			body = reader
+		}
+
+		data, err := io.ReadAll(body)
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		s.requests = append(s.requests, string(data))
+
+		if len(s.status) > 0 {
+			w.WriteHeader(s.status[0])
+			s.status = s.status[1:]
+		}
+	}))
+	return s
+}
+
+func (s *testHTTPServer) received() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func TestHTTPAppenderBatchSize(t *testing.T) {
+	server := newTestHTTPServer(t)
+	defer server.server.Close()
+	appender := NewHTTPAppender(server.server.URL, HTTPAppenderOptions{
+		Header:     http.Header{"Authorization": []string{"token"}},
+		BatchSize:  3,
+		MaxLatency: time.Minute,
+	})
+	l := NewLogger(appender, appender)
+	l.SetFormatter(NewJSONFormatter(""))
+
+	for i := 0; i < 7; i++ {
+		l.Info("entry %d", i)
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := server.received()
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected three batches, but was: %v", requests)
+	}
+
+	for i, n := range []int{3, 3, 1} {
+		if lines := strings.Count(requests[i], "\n"); lines != n {
+			t.Fatalf("Expected %d entries in batch %d, but was: %q", n, i, requests[i])
+		}
+	}
+
+	if !strings.HasPrefix(requests[0], `{"level":"info","msg":"entry %d","params":[0]}`+"\n") {
+		t.Fatalf("Unexpected batch: %q", requests[0])
+	}
+}
+
+func TestHTTPAppenderBatchBytesAndLatency(t *testing.T) {
+	server := newTestHTTPServer(t)
+	defer server.server.Close()
+	appender := NewHTTPAppender(server.server.URL, HTTPAppenderOptions{
+		Header:             http.Header{"Authorization": []string{"token"}},
+		BatchBytes:         10,
+		MaxLatency:         time.Millisecond * 20,
+		DisableCompression: true,
+	})
+
+	for _, entry := range []string{"12345\n", "12345", "1"} {
+		if _, err := appender.Write([]byte(entry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	requests := server.received()
+
+	if len(requests) != 2 || requests[0] != "12345\n" || requests[1] != "12345\n1\n" {
+		t.Fatalf("Unexpected batches: %q", requests)
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHTTPAppenderRetry(t *testing.T) {
+	server := newTestHTTPServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK, http.StatusBadRequest)
+	defer server.server.Close()
+	var m sync.Mutex
+	var errs []error
+	appender := NewHTTPAppender(server.server.URL, HTTPAppenderOptions{
+		Header:     http.Header{"Authorization": []string{"token"}},
+		BatchSize:  1,
+		MinBackoff: time.Millisecond,
+		ErrorHandler: func(err error) {
+			m.Lock()
+			defer m.Unlock()
+			errs = append(errs, err)
+		},
+	})
+
+	if _, err := appender.Write([]byte("retried\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	appender.Flush()
+
+	if _, err := appender.Write([]byte("rejected\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests := server.received(); len(requests) != 4 {
+		t.Fatalf("Expected first batch to be retried, but was: %q", requests)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if len(errs) != 1 || errs[0].(*HTTPStatusError).StatusCode != http.StatusBadRequest {
+		t.Fatalf("Client errors must not be retried, but was: %v", errs)
+	}
+
+	if appender.Dropped() != 1 {
+		t.Fatalf("Rejected entry must have been dropped, but was: %v", appender.Dropped())
+	}
+}
+
+func TestHTTPAppenderRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	appender := NewHTTPAppender(server.URL, HTTPAppenderOptions{
+		BatchSize:  1,
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+	})
+	start := time.Now()
+
+	if _, err := appender.Write([]byte("retried\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	appender.Flush()
+
+	if time.Since(start) > time.Second || appender.Dropped() != 1 {
+		t.Fatalf("Retry-After must be limited to the maximum backoff, but was: %v %v", time.Since(start), appender.Dropped())
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHTTPAppenderCloseDuringBackoff(t *testing.T) {
+	server := newTestHTTPServer(t, http.StatusServiceUnavailable)
+	defer server.server.Close()
+	appender := NewHTTPAppender(server.server.URL, HTTPAppenderOptions{
+		Header:     http.Header{"Authorization": []string{"token"}},
+		BatchSize:  1,
+		MinBackoff: time.Hour,
+	})
+
+	if _, err := appender.Write([]byte("retried\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait for the first request to fail
+	for len(server.received()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > time.Second || appender.Dropped() != 1 {
+		t.Fatalf("Close must not wait for the backoff, but was: %v %v", time.Since(start), appender.Dropped())
+	}
+
+	if _, err := appender.Write([]byte("closed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	appender.Flush()
+
+	if requests := server.received(); len(requests) != 1 || appender.Dropped() != 2 {
+		t.Fatalf("Entries written after close must be dropped, but was: %q %v", requests, appender.Dropped())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != time.Second*3 {
+		t.Fatalf("Unexpected duration: %v", d)
+	}
+
+	if d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); d <= time.Second*55 || d > time.Minute {
+		t.Fatalf("Unexpected duration: %v", d)
+	}
+
+	for _, value := range []string{"", "-1", "invalid", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)} {
+		if d := parseRetryAfter(value); d != 0 {
+			t.Fatalf("Unexpected duration for %q: %v", value, d)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package logbuch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultJSONTimeKey    = "time"
+	defaultJSONLevelKey   = "level"
+	defaultJSONMessageKey = "msg"
+	defaultJSONParamsKey  = "params"
+	defaultJSONCallerKey  = "caller"
+	defaultJSONStackKey   = "stack"
+	jsonFieldKeyPrefix    = "fields."
+)
+
+// JSONFormatter prints log messages as JSON objects, one per line.
+// Each object contains the timestamp, the log level and the message. The message won't be formatted.
+// If the first and only parameter is of type Fields, the key value pairs are added to the object as top-level keys.
+// Keys colliding with the time, level, message, params, caller or stack key are prefixed with "fields.".
+//
+// Example:
+//  logbuch.Debug("Hello World!", logbuch.Fields{"integer": 123, "string": "test"})
+//
+// Output:
+//  {"time":"2019-09-19T17:39:02.4326139+02:00","level":"debug","msg":"Hello World!","integer":123,"string":"test"}
+//
+// If there is more than one parameter or the type of the parameter is different,
+// all parameters will be added as an array using the params key.
+// Values that cannot be serialized, like channels or functions, are printed using fmt instead.
+// Errors are serialized using their Error() method.
+type JSONFormatter struct {
+	timeFormat  string
+	disableTime bool
+
+	// TimeKey is the key used for the timestamp ("time" by default).
+	TimeKey string
+
+	// LevelKey is the key used for the log level ("level" by default).
+	LevelKey string
+
+	// MessageKey is the key used for the message ("msg" by default).
+	MessageKey string
+
+	// ParamsKey is the key used for parameters which are not of type Fields ("params" by default).
+	ParamsKey string
+
+	// CallerKey is the key used for the caller if enabled ("caller" by default).
+	CallerKey string
+
+	// StackKey is the key used for the stack trace if enabled ("stack" by default).
+	StackKey string
+}
+
+// NewJSONFormatter creates a new JSONFormatter with given timestamp format.
+// The timestamp can be disabled by passing an empty string.
+func NewJSONFormatter(timeFormat string) *JSONFormatter {
+	return &JSONFormatter{timeFormat: timeFormat,
+		disableTime: timeFormat == "",
+		TimeKey:     defaultJSONTimeKey,
+		LevelKey:    defaultJSONLevelKey,
+		MessageKey:  defaultJSONMessageKey,
+		ParamsKey:   defaultJSONParamsKey,
+		CallerKey:   defaultJSONCallerKey,
+		StackKey:    defaultJSONStackKey}
+}
+
+// Fmt formats the message as described for the JSONFormatter.
+func (formatter *JSONFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	formatter.FmtEntry(buffer, &Entry{Level: level, Time: t, Message: msg, Params: params})
+}
+
+// FmtEntry formats the entry as described for the JSONFormatter.
+// Fields bound to the logger are added as top-level keys.
+func (formatter *JSONFormatter) FmtEntry(buffer *[]byte, entry *Entry) {
+	*buffer = append(*buffer, '{')
+
+	if !formatter.disableTime {
+		formatter.appendKey(buffer, formatter.TimeKey)
+		*buffer = appendJSONValue(*buffer, entry.Time.Format(formatter.timeFormat))
+	}
+
+	formatter.appendKey(buffer, formatter.LevelKey)
+	*buffer = appendJSONValue(*buffer, levelName(entry.Level))
+
+	if entry.Caller != nil {
+		formatter.appendKey(buffer, formatter.CallerKey)
+		*buffer = appendJSONValue(*buffer, entry.Caller.String())
+	}
+
+	formatter.appendKey(buffer, formatter.MessageKey)
+	*buffer = appendJSONValue(*buffer, entry.Message)
+	fields, params := mergeFields(entry.Fields, entry.Params)
+	formatter.appendFields(buffer, fields)
+
+	if len(params) > 0 {
+		formatter.appendKey(buffer, formatter.ParamsKey)
+		*buffer = append(*buffer, '[')
+
+		for i, v := range params {
+			if i > 0 {
+				*buffer = append(*buffer, ',')
+			}
+
+			*buffer = appendJSONValue(*buffer, v)
+		}
+
+		*buffer = append(*buffer, ']')
+	}
+
+	if entry.Stack != "" {
+		formatter.appendKey(buffer, formatter.StackKey)
+		*buffer = appendJSONValue(*buffer, entry.Stack)
+	}
+
+	*buffer = append(*buffer, "}\n"...)
+}
+
+// Pnc panics with the message as it appears in the JSON object.
+func (formatter *JSONFormatter) Pnc(msg string, params []interface{}) {
+	panic(msg)
+}
+
+func (formatter *JSONFormatter) appendFields(buffer *[]byte, fields Fields) {
+	for _, k := range sortedKeys(fields) {
+		key := k
+
+		if formatter.isReservedKey(key) {
+			key = jsonFieldKeyPrefix + key
+		}
+
+		formatter.appendKey(buffer, key)
+		*buffer = appendJSONValue(*buffer, fields[k])
+	}
+}
+
+func (formatter *JSONFormatter) appendKey(buffer *[]byte, key string) {
+	if len(*buffer) > 0 && (*buffer)[len(*buffer)-1] != '{' {
+		*buffer = append(*buffer, ',')
+	}
+
+	*buffer = appendJSONValue(*buffer, key)
+	*buffer = append(*buffer, ':')
+}
+
+func (formatter *JSONFormatter) isReservedKey(key string) bool {
+	return (key == formatter.TimeKey && !formatter.disableTime) ||
+		key == formatter.LevelKey ||
+		key == formatter.MessageKey ||
+		key == formatter.ParamsKey ||
+		key == formatter.CallerKey ||
+		key == formatter.StackKey
+}
+
+func appendJSONValue(buffer []byte, v interface{}) []byte {
+	if err, ok := v.(error); ok && err != nil {
+		if isNilPointer(err) {
+			v = nil
+		} else if _, ok := v.(json.Marshaler); !ok {
+			v = err.Error()
+		}
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		// the value cannot be serialized (channels, functions, NaN, ...), so we fall back to fmt
+		out.Reset()
+
+		if err := enc.Encode(fmt.Sprintf("%v", v)); err != nil {
+			return append(buffer, "null"...)
+		}
+	}
+
+	return append(buffer, bytes.TrimRight(out.Bytes(), "\n")...)
+}
@@ -0,0 +1,122 @@
+package logbuch
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	formatter := NewJSONFormatter(StandardTimeFormat)
+	now := time.Now()
+	nowStr := now.Format(StandardTimeFormat)
+	var buffer []byte
+	input := []struct {
+		level  int
+		msg    string
+		params []interface{}
+	}{
+		{LevelDebug, "Hello World!", nil},
+		{LevelInfo, "Hello World!", []interface{}{"test", 123}},
+		{LevelWarning, "Hello \"World\"!", []interface{}{Fields{"text": "test", "integer": 123}}},
+		{LevelError, "Hello\nWorld!", []interface{}{Fields{"float": -3.14, "err": errors.New("error"), "msg": "collision"}}},
+	}
+	expected := []string{
+		`{"time":"` + nowStr + `","level":"debug","msg":"Hello World!"}` + "\n",
+		`{"time":"` + nowStr + `","level":"info","msg":"Hello World!","params":["test",123]}` + "\n",
+		`{"time":"` + nowStr + `","level":"warn","msg":"Hello \"World\"!","integer":123,"text":"test"}` + "\n",
+		`{"time":"` + nowStr + `","level":"error","msg":"Hello\nWorld!","err":"error","float":-3.14,"fields.msg":"collision"}` + "\n",
+	}
+
+	for i, in := range input {
+		buffer = buffer[:0]
+		formatter.Fmt(&buffer, in.level, now, in.msg, in.params)
+		out := string(buffer)
+		t.Log(out)
+
+		if out != expected[i] {
+			t.Fatalf("Expected '%v' but was: %v", expected[i], out)
+		}
+
+		if !json.Valid(buffer) {
+			t.Fatalf("Output must be valid JSON: %v", out)
+		}
+	}
+}
+
+func TestJSONFormatterNilError(t *testing.T) {
+	formatter := NewJSONFormatter("")
+	var buffer []byte
+	formatter.Fmt(&buffer, LevelInfo, time.Now(), "message", []interface{}{Fields{"err": (*os.PathError)(nil)}})
+
+	if string(buffer) != `{"level":"info","msg":"message","err":null}`+"\n" {
+		t.Fatalf("Typed nil errors must be null, but was: %v", string(buffer))
+	}
+}
+
+func TestJSONFormatterNonSerializable(t *testing.T) {
+	formatter := NewJSONFormatter("")
+	var buffer []byte
+	formatter.Fmt(&buffer, LevelInfo, time.Now(), "message", []interface{}{Fields{"chan": make(chan int), "func": func() {}}})
+
+	if !json.Valid(buffer) {
+		t.Fatalf("Output must be valid JSON: %v", string(buffer))
+	}
+
+	var out map[string]interface{}
+
+	if err := json.Unmarshal(buffer, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := out["chan"].(string); !ok {
+		t.Fatalf("Channel must have been printed as string, but was: %v", out["chan"])
+	}
+
+	if _, ok := out["func"].(string); !ok {
+		t.Fatalf("Function must have been printed as string, but was: %v", out["func"])
+	}
+}
+
+func TestJSONFormatterKeys(t *testing.T) {
+	formatter := NewJSONFormatter("")
+	formatter.LevelKey = "severity"
+	formatter.MessageKey = "message"
+	formatter.ParamsKey = "args"
+	var buffer []byte
+	formatter.Fmt(&buffer, LevelInfo, time.Now(), "<html>", []interface{}{1, "two"})
+	expected := `{"severity":"info","message":"<html>","args":[1,"two"]}` + "\n"
+
+	if string(buffer) != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, string(buffer))
+	}
+}
+
+func TestJSONFormatterPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Formatter must panic")
+		} else {
+			if r != "message %s" {
+				t.Fatalf("Message not correct: %v", r)
+			}
+		}
+	}()
+
+	formatter := NewJSONFormatter(StandardTimeFormat)
+	formatter.Pnc("message %s", []interface{}{"param"})
+}
+
+func TestJSONFormatterLogger(t *testing.T) {
+	var buffer strings.Builder
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewJSONFormatter(""))
+	logger.Info("Hello World!", Fields{"key": "value"})
+
+	if buffer.String() != `{"level":"info","msg":"Hello World!","key":"value"}`+"\n" {
+		t.Fatalf("Unexpected log: %v", buffer.String())
+	}
+}
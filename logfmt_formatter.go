@@ -0,0 +1,174 @@
+package logbuch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is a single key value pair used by OrderedFields.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedFields is used instead of Fields to keep the key value pairs in insertion order.
+//
+// Example:
+//  logbuch.Debug("Hello World!", logbuch.OrderedFields{{"string", "test"}, {"integer", 123}})
+type OrderedFields []Field
+
+// LogfmtFormatter prints log messages in logfmt style. The message won't be formatted.
+// Each line starts with the timestamp, followed by the log level, the message and key value pairs.
+// To add key value pairs, the first and only parameter must be of type Fields or OrderedFields.
+// Fields are sorted by key, OrderedFields keep their order.
+//
+// Example:
+//  logbuch.Debug("Hello World!", logbuch.Fields{"integer": 123, "string": "hello world"})
+//
+// Output:
+//  time=2019-09-19T17:39:02.4326139+02:00 level=debug msg="Hello World!" integer=123 string="hello world"
+//
+// If there is more than one parameter or the type of the parameter is different,
+// all parameters will be added using the params key.
+// Values containing spaces, quotes, equal signs or control characters are quoted.
+type LogfmtFormatter struct {
+	timeFormat  string
+	disableTime bool
+}
+
+// NewLogfmtFormatter creates a new LogfmtFormatter with given timestamp format.
+// The timestamp can be disabled by passing an empty string.
+func NewLogfmtFormatter(timeFormat string) *LogfmtFormatter {
+	return &LogfmtFormatter{timeFormat: timeFormat, disableTime: timeFormat == ""}
+}
+
+// Fmt formats the message as described for the LogfmtFormatter.
+func (formatter *LogfmtFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	formatter.FmtEntry(buffer, &Entry{Level: level, Time: t, Message: msg, Params: params})
+}
+
+// FmtEntry formats the entry as described for the LogfmtFormatter.
+// Fields bound to the logger are added in front of OrderedFields or merged with Fields.
+func (formatter *LogfmtFormatter) FmtEntry(buffer *[]byte, entry *Entry) {
+	if !formatter.disableTime {
+		*buffer = appendLogfmtPair(*buffer, "time", entry.Time.Format(formatter.timeFormat))
+		*buffer = append(*buffer, ' ')
+	}
+
+	*buffer = appendLogfmtPair(*buffer, "level", levelName(entry.Level))
+	*buffer = append(*buffer, ' ')
+
+	if entry.Caller != nil {
+		*buffer = appendLogfmtPair(*buffer, "caller", entry.Caller.String())
+		*buffer = append(*buffer, ' ')
+	}
+
+	*buffer = appendLogfmtMessage(*buffer, entry.Message, entry.Fields, entry.Params)
+
+	if entry.Stack != "" {
+		*buffer = append(*buffer, ' ')
+		*buffer = appendLogfmtPair(*buffer, "stack", entry.Stack)
+	}
+
+	*buffer = append(*buffer, '\n')
+}
+
+// Pnc panics with the message and key value pairs in logfmt style.
+func (formatter *LogfmtFormatter) Pnc(msg string, params []interface{}) {
+	panic(string(appendLogfmtMessage(nil, msg, nil, params)))
+}
+
+func appendLogfmtMessage(buffer []byte, msg string, bound Fields, params []interface{}) []byte {
+	buffer = appendLogfmtPair(buffer, "msg", msg)
+
+	if len(params) == 1 {
+		if fields, ok := params[0].(OrderedFields); ok {
+			return appendLogfmtOrderedFields(appendLogfmtFields(buffer, bound), fields)
+		}
+	}
+
+	fields, params := mergeFields(bound, params)
+	buffer = appendLogfmtFields(buffer, fields)
+
+	if len(params) > 0 {
+		buffer = append(buffer, ' ')
+		buffer = appendLogfmtPair(buffer, "params", fmt.Sprintf("%v", params))
+	}
+
+	return buffer
+}
+
+func appendLogfmtFields(buffer []byte, fields Fields) []byte {
+	for _, k := range sortedKeys(fields) {
+		buffer = append(buffer, ' ')
+		buffer = appendLogfmtPair(buffer, k, fields[k])
+	}
+
+	return buffer
+}
+
+func appendLogfmtOrderedFields(buffer []byte, fields OrderedFields) []byte {
+	for _, field := range fields {
+		buffer = append(buffer, ' ')
+		buffer = appendLogfmtPair(buffer, field.Key, field.Value)
+	}
+
+	return buffer
+}
+
+func appendLogfmtPair(buffer []byte, key string, value interface{}) []byte {
+	buffer = appendLogfmtKey(buffer, key)
+	buffer = append(buffer, '=')
+	var str string
+
+	switch v := value.(type) {
+	case nil:
+		return buffer
+	case string:
+		str = v
+	case error:
+		if isNilPointer(v) {
+			return buffer
+		}
+
+		str = v.Error()
+	default:
+		str = fmt.Sprintf("%v", v)
+	}
+
+	if logfmtNeedsQuotes(str) {
+		return strconv.AppendQuote(buffer, str)
+	}
+
+	return append(buffer, str...)
+}
+
+func appendLogfmtKey(buffer []byte, key string) []byte {
+	if key == "" {
+		return append(buffer, '_')
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || unicode.IsSpace(r) || unicode.IsControl(r) {
+			buffer = append(buffer, '_')
+		} else {
+			buffer = append(buffer, string(r)...)
+		}
+	}
+
+	return buffer
+}
+
+func logfmtNeedsQuotes(str string) bool {
+	if str == "" {
+		return true
+	}
+
+	return strings.IndexFunc(str, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || unicode.IsSpace(r) || unicode.IsControl(r)
+	}) != -1
+}
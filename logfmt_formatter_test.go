@@ -0,0 +1,85 @@
+package logbuch
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	formatter := NewLogfmtFormatter(StandardTimeFormat)
+	now := time.Now()
+	nowStr := now.Format(StandardTimeFormat)
+	var buffer []byte
+	input := []struct {
+		level  int
+		msg    string
+		params []interface{}
+	}{
+		{LevelDebug, "Hello", nil},
+		{LevelInfo, "Hello World!", []interface{}{"test", 123}},
+		{LevelWarning, "Hello \"World\"!", []interface{}{Fields{"text": "a=b", "integer": 123, "empty": "", "nil": nil}}},
+		{LevelError, "Hello\nWorld!", []interface{}{OrderedFields{{"z", -3.14}, {"a b", errors.New("some error")}, {"nil", (*os.PathError)(nil)}}}},
+	}
+	expected := []string{
+		"time=" + nowStr + " level=debug msg=Hello\n",
+		"time=" + nowStr + " level=info msg=\"Hello World!\" params=\"[test 123]\"\n",
+		"time=" + nowStr + " level=warn msg=\"Hello \\\"World\\\"!\" empty=\"\" integer=123 nil= text=\"a=b\"\n",
+		"time=" + nowStr + " level=error msg=\"Hello\\nWorld!\" z=-3.14 a_b=\"some error\" nil=\n",
+	}
+
+	for i, in := range input {
+		buffer = buffer[:0]
+		formatter.Fmt(&buffer, in.level, now, in.msg, in.params)
+		out := string(buffer)
+		t.Log(out)
+
+		if out != expected[i] {
+			t.Fatalf("Expected '%v' but was: %v", expected[i], out)
+		}
+	}
+}
+
+func TestLogfmtFormatterStableOrder(t *testing.T) {
+	formatter := NewLogfmtFormatter("")
+	fields := Fields{"c": 3, "a": 1, "b": 2, "d": 4, "e": 5}
+	var buffer []byte
+
+	for i := 0; i < 20; i++ {
+		buffer = buffer[:0]
+		formatter.Fmt(&buffer, LevelInfo, time.Now(), "message", []interface{}{fields})
+
+		if string(buffer) != "level=info msg=message a=1 b=2 c=3 d=4 e=5\n" {
+			t.Fatalf("Unexpected log: %v", string(buffer))
+		}
+	}
+}
+
+func TestLogfmtFormatterPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Formatter must panic")
+		} else {
+			if r != "msg=\"some message\" key=value" {
+				t.Fatalf("Message not correct: %v", r)
+			}
+		}
+	}()
+
+	formatter := NewLogfmtFormatter(StandardTimeFormat)
+	formatter.Pnc("some message", []interface{}{Fields{"key": "value"}})
+}
+
+func TestLogfmtFormatterEntryFields(t *testing.T) {
+	formatter := NewLogfmtFormatter("")
+	var buffer []byte
+	formatter.FmtEntry(&buffer, &Entry{Level: LevelInfo,
+		Message: "message",
+		Params:  []interface{}{OrderedFields{{"z", 1}, {"a", 2}}},
+		Fields:  Fields{"bound": true}})
+
+	if string(buffer) != "level=info msg=message bound=true z=1 a=2\n" {
+		t.Fatalf("Unexpected log: %v", string(buffer))
+	}
+}
@@ -1,6 +1,7 @@
 package logbuch
 
 import (
+	"context"
 	"io"
 	"sync"
 	"time"
@@ -20,22 +21,44 @@ const (
 	LevelError
 )
 
+// LevelWriter is an optional interface for io.Writers used by the Logger.
+// If an output implements it, WriteLevel is called instead of Write, passing the level of the log entry.
+type LevelWriter interface {
+	io.Writer
+
+	// WriteLevel writes the formatted log entry of given level.
+	WriteLevel(int, []byte) (int, error)
+}
+
 // Logger writes messages to different io.Writers depending on the log level by using a Formatter.
 type Logger struct {
+	dropped    uint64 // must be the first field for 64-bit alignment of atomic operations
 	m          sync.Mutex
 	level      int
+	caller     int32 // accessed atomically, as it's read on every log call
+	stackTrace int32 // level + 1 or 0 if disabled, accessed atomically, as it's read on every log call
+	sampler    sampler
 	formatter  Formatter
 	debugOut   io.Writer
 	infoOut    io.Writer
 	warningOut io.Writer
 	errorOut   io.Writer
 	buffer     []byte
+	sinks      []*Sink
+	hooks      [LevelError + 1][]*Hook
+	parent     *Logger
+	fields     Fields
+	asyncM     sync.RWMutex
+	async      *asyncQueue
 
 	// PanicOnErr enables panics if the logger cannot write to log output.
+	// It's ignored in asynchronous mode, as the panic could not be recovered by the caller. Use AsyncOptions.ErrorHandler instead.
+	// Loggers created using With use the setting of the Logger they were derived from.
 	PanicOnErr bool
 }
 
 // NewLogger creates a new logger using the StandardFormatter for given io.Writers.
+// The writers and formatter make up the default sink. Pass nil writers to disable it, if you only want to use sinks added by AddSink.
 func NewLogger(stdout, stderr io.Writer) *Logger {
 	return &Logger{formatter: NewStandardFormatter(StandardTimeFormat),
 		debugOut:   stdout,
@@ -46,6 +69,7 @@ func NewLogger(stdout, stderr io.Writer) *Logger {
 
 // SetLevel sets the log level.
 func (log *Logger) SetLevel(level int) {
+	log = log.root()
 	log.m.Lock()
 	defer log.m.Unlock()
 	log.level = getValidLevel(level)
@@ -53,11 +77,12 @@ func (log *Logger) SetLevel(level int) {
 
 // GetLevel returns the log level.
 func (log *Logger) GetLevel() int {
-	return log.level
+	return log.root().level
 }
 
 // SetFormatter sets the formatter.
 func (log *Logger) SetFormatter(formatter Formatter) {
+	log = log.root()
 	log.m.Lock()
 	defer log.m.Unlock()
 	log.formatter = formatter
@@ -65,11 +90,12 @@ func (log *Logger) SetFormatter(formatter Formatter) {
 
 // GetFormatter returns the formatter.
 func (log *Logger) GetFormatter() Formatter {
-	return log.formatter
+	return log.root().formatter
 }
 
 // SetOut sets the io.Writer for given level.
 func (log *Logger) SetOut(level int, out io.Writer) {
+	log = log.root()
 	log.m.Lock()
 	defer log.m.Unlock()
 
@@ -87,6 +113,8 @@ func (log *Logger) SetOut(level int, out io.Writer) {
 
 // GetOut returns the io.Writer for given level.
 func (log *Logger) GetOut(level int) io.Writer {
+	log = log.root()
+
 	switch level {
 	case LevelDebug:
 		return log.debugOut
@@ -99,62 +127,222 @@ func (log *Logger) GetOut(level int) io.Writer {
 	}
 }
 
+// With returns a new Logger which adds given fields to each log entry.
+// The new Logger shares the outputs, level and formatter with the Logger it was derived from.
+// Calling With on a derived Logger accumulates the fields, overriding existing keys.
+// The fields are passed to formatters implementing the EntryFormatter interface.
+func (log *Logger) With(fields Fields) *Logger {
+	bound := make(Fields, len(log.fields)+len(fields))
+
+	for k, v := range log.fields {
+		bound[k] = v
+	}
+
+	for k, v := range fields {
+		bound[k] = v
+	}
+
+	return &Logger{parent: log.root(), fields: bound}
+}
+
 // Debug logs a formatted debug message.
 func (log *Logger) Debug(msg string, params ...interface{}) {
-	if log.level <= LevelDebug {
-		log.log(LevelDebug, msg, params)
-	}
+	log.logLevel(1, LevelDebug, nil, msg, params)
 }
 
 // Info logs a formatted info message.
 func (log *Logger) Info(msg string, params ...interface{}) {
-	if log.level <= LevelInfo {
-		log.log(LevelInfo, msg, params)
-	}
+	log.logLevel(1, LevelInfo, nil, msg, params)
 }
 
 // Warn logs a formatted warning message.
 func (log *Logger) Warn(msg string, params ...interface{}) {
-	if log.level <= LevelWarning {
-		log.log(LevelWarning, msg, params)
-	}
+	log.logLevel(1, LevelWarning, nil, msg, params)
 }
 
 // Error logs a formatted error message.
 func (log *Logger) Error(msg string, params ...interface{}) {
-	// maximum level cannot be disabled
-	log.log(LevelError, msg, params)
+	log.logLevel(1, LevelError, nil, msg, params)
+}
+
+// DebugCtx logs a formatted debug message adding the fields extracted from the context.
+func (log *Logger) DebugCtx(ctx context.Context, msg string, params ...interface{}) {
+	log.logLevel(1, LevelDebug, ctx, msg, params)
+}
+
+// InfoCtx logs a formatted info message adding the fields extracted from the context.
+func (log *Logger) InfoCtx(ctx context.Context, msg string, params ...interface{}) {
+	log.logLevel(1, LevelInfo, ctx, msg, params)
+}
+
+// WarnCtx logs a formatted warning message adding the fields extracted from the context.
+func (log *Logger) WarnCtx(ctx context.Context, msg string, params ...interface{}) {
+	log.logLevel(1, LevelWarning, ctx, msg, params)
+}
+
+// ErrorCtx logs a formatted error message adding the fields extracted from the context.
+func (log *Logger) ErrorCtx(ctx context.Context, msg string, params ...interface{}) {
+	log.logLevel(1, LevelError, ctx, msg, params)
 }
 
 // Fatal logs a formatted error message and panics.
+// In asynchronous mode, all queued entries are written before.
 func (log *Logger) Fatal(msg string, params ...interface{}) {
-	log.Error(msg, params...)
-	log.formatter.Pnc(msg, params)
+	log.fatal(1, msg, params)
+}
+
+// logLevel logs the message if the level is enabled, adding the fields extracted from the context if set.
+// The skip is the number of stack frames between the caller of the logger and logLevel,
+// which is 1 for all methods and package level functions calling it directly.
+func (log *Logger) logLevel(skip, level int, ctx context.Context, msg string, params []interface{}) {
+	// maximum level cannot be disabled
+	if level < LevelError && log.GetLevel() > level {
+		return
+	}
+
+	if !log.sample(level, msg) {
+		return
+	}
+
+	fields := log.fields
+
+	if ctx != nil {
+		fields = contextFields(ctx, fields)
+	}
+
+	log.log(skip+1, level, fields, msg, params)
+}
+
+// fatal logs the message without sampling it, as it's the last entry logged before the program stops.
+func (log *Logger) fatal(skip int, msg string, params []interface{}) {
+	entry := log.newEntry(skip+1, LevelError, log.fields, msg, params)
+	entry.Fatal = true
+	log.logEntry(entry)
+	log.Flush()
+	log.GetFormatter().Pnc(msg, params)
+}
+
+func (log *Logger) log(skip, level int, fields Fields, msg string, params []interface{}) {
+	log.logEntry(log.newEntry(skip+1, level, fields, msg, params))
+}
+
+// newEntry creates the entry and captures the caller and stack trace if enabled.
+// A negative skip disables capturing the caller and stack trace, as the location is unknown.
+func (log *Logger) newEntry(skip, level int, fields Fields, msg string, params []interface{}) *Entry {
+	entry := &Entry{Level: level, Time: time.Now(), Message: msg, Params: params, Fields: fields}
+
+	if skip >= 0 && log.GetReportCaller() {
+		entry.Caller = callerAt(skip + 1)
+	}
+
+	if log.stackTraceEnabled(level) {
+		entry.Stack = errorStack(params)
+
+		if entry.Stack == "" && skip >= 0 {
+			entry.Stack = stackAt(skip + 1)
+		}
+	}
+
+	return entry
 }
 
-func (log *Logger) log(level int, msg string, params []interface{}) {
-	now := time.Now()
+func (log *Logger) logEntry(entry *Entry) {
+	log = log.root()
+	log.asyncM.RLock()
+
+	if log.async != nil {
+		log.async.enqueue(entry)
+		log.asyncM.RUnlock()
+		return
+	}
+
+	log.asyncM.RUnlock()
+	log.write(entry)
+}
+
+func (log *Logger) write(entry *Entry) {
+	// panic in case the logger cannot write to the configured io.Writer and panic is enabled
+	if err := log.writeEntry(entry); err != nil && log.PanicOnErr {
+		panic(err)
+	}
+}
+
+// writeEntry formats the entry and writes it to the outputs and sinks, returning the first error.
+func (log *Logger) writeEntry(entry *Entry) error {
 	log.m.Lock()
 	defer log.m.Unlock()
+	hooks := log.hooks[getValidLevel(entry.Level)]
+
+	if !fireBefore(hooks, entry) {
+		return nil
+	}
+
 	log.buffer = log.buffer[:0]
-	log.formatter.Fmt(&log.buffer, level, now, msg, params)
+	formatEntry(log.formatter, &log.buffer, entry)
+
+	if !fireAfter(hooks, entry, log.buffer) {
+		return nil
+	}
+
 	var err error
 
-	switch level {
-	case LevelDebug:
-		_, err = log.debugOut.Write(log.buffer)
-	case LevelInfo:
-		_, err = log.infoOut.Write(log.buffer)
-	case LevelWarning:
-		_, err = log.warningOut.Write(log.buffer)
-	default:
-		_, err = log.errorOut.Write(log.buffer)
+	// nothing to write, as the formatter dropped the message or forwarded it somewhere else
+	if len(log.buffer) > 0 {
+		switch entry.Level {
+		case LevelDebug:
+			err = writeLevel(log.debugOut, entry.Level, log.buffer)
+		case LevelInfo:
+			err = writeLevel(log.infoOut, entry.Level, log.buffer)
+		case LevelWarning:
+			err = writeLevel(log.warningOut, entry.Level, log.buffer)
+		default:
+			err = writeLevel(log.errorOut, entry.Level, log.buffer)
+		}
 	}
 
-	// panic in case the logger cannot write to the configured io.Writer and panic is enabled
-	if err != nil && log.PanicOnErr {
-		panic(err)
+	// a failing sink must not stop the others
+	for _, sink := range log.sinks {
+		if e := sink.write(entry); e != nil && err == nil {
+			err = e
+		}
 	}
+
+	return err
+}
+
+func (log *Logger) root() *Logger {
+	if log.parent != nil {
+		return log.parent
+	}
+
+	return log
+}
+
+// formatEntry formats the entry using given Formatter, preferring the EntryFormatter interface if implemented.
+func formatEntry(formatter Formatter, buffer *[]byte, entry *Entry) {
+	if f, ok := formatter.(EntryFormatter); ok {
+		f.FmtEntry(buffer, entry)
+	} else {
+		formatter.Fmt(buffer, entry.Level, entry.Time, entry.Message, entry.Params)
+	}
+}
+
+// writeLevel writes the data to given io.Writer, passing on the level if it implements the LevelWriter interface.
+// Nothing is written if the io.Writer is nil.
+func writeLevel(out io.Writer, level int, p []byte) error {
+	if out == nil {
+		return nil
+	}
+
+	var err error
+
+	if writer, ok := out.(LevelWriter); ok {
+		_, err = writer.WriteLevel(level, p)
+	} else {
+		_, err = out.Write(p)
+	}
+
+	return err
 }
 
 func getValidLevel(level int) int {
@@ -144,3 +144,41 @@ func TestNewLoggerRollingFileAppender(t *testing.T) {
 		t.Fatalf("Error log must contain log output, but was: %v", string(errFile))
 	}
 }
+
+func TestLoggerWith(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewStandardFormatter(""))
+	child := logger.With(Fields{"request_id": 42, "user": "foo"})
+	nested := child.With(Fields{"user": "bar", "tenant": "emvi"})
+	child.Info("Hello %s!", "World")
+	nested.Info("Nested")
+	logger.Info("Parent")
+	expected := "[INFO ] Hello World! request_id=42 user=foo\n" +
+		"[INFO ] Nested request_id=42 tenant=emvi user=bar\n" +
+		"[INFO ] Parent\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, buffer.String())
+	}
+
+	logger.SetLevel(LevelWarning)
+	nested.Info("Must not be logged")
+
+	if nested.GetLevel() != LevelWarning || buffer.String() != expected {
+		t.Fatalf("Derived logger must share the level with its parent, but was: %v", buffer.String())
+	}
+
+	var out bytes.Buffer
+	nested.SetOut(LevelError, &out)
+	nested.SetFormatter(NewJSONFormatter(""))
+	nested.Error("Error", Fields{"code": 123})
+
+	if out.String() != `{"level":"error","msg":"Error","code":123,"request_id":42,"tenant":"emvi","user":"bar"}`+"\n" {
+		t.Fatalf("Unexpected log: %v", out.String())
+	}
+
+	if logger.GetOut(LevelError) != &out || logger.GetFormatter() != nested.GetFormatter() {
+		t.Fatal("Derived logger must share outputs and formatter with its parent")
+	}
+}
@@ -0,0 +1,327 @@
+package logbuch
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	patternName = "{name}"
+	patternDate = "{date}"
+	patternSeq  = "{seq}"
+	patternHost = "{host}"
+	patternPID  = "{pid}"
+
+	// seqPerDate is the maximum number of sequence numbers per date used to order files created by the PatternNameSchema.
+	seqPerDate = 1000000
+)
+
+var patternPlaceholder = regexp.MustCompile(`\{(name|date|seq|host|pid)\}`)
+
+// SequenceNameSchema names log files using an increasing sequence number, like app-1.log, app-2.log, ...
+// On startup, it resumes from the highest number found in the log directory, including compressed files.
+type SequenceNameSchema struct {
+	m       sync.Mutex
+	dir     string
+	prefix  string
+	suffix  string
+	seq     int64
+	scanned bool
+}
+
+// NewSequenceNameSchema creates a new SequenceNameSchema for given log directory.
+// File names start with the prefix, followed by the sequence number and the suffix.
+func NewSequenceNameSchema(dir, prefix, suffix string) *SequenceNameSchema {
+	return &SequenceNameSchema{dir: dir, prefix: prefix, suffix: suffix}
+}
+
+// Name returns the next file name.
+func (schema *SequenceNameSchema) Name() string {
+	schema.m.Lock()
+	defer schema.m.Unlock()
+
+	if !schema.scanned {
+		schema.scanned = true
+		scanFileNames(schema.dir, func(name string) bool {
+			seq, ok := schema.Match(name)
+
+			if ok && seq > schema.seq {
+				schema.seq = seq
+			}
+
+			return ok
+		})
+	}
+
+	schema.seq++
+	return schema.prefix + strconv.FormatInt(schema.seq, 10) + schema.suffix
+}
+
+// Match returns the sequence number if the file name has been created by this schema.
+func (schema *SequenceNameSchema) Match(name string) (int64, bool) {
+	if len(name) <= len(schema.prefix)+len(schema.suffix) ||
+		!strings.HasPrefix(name, schema.prefix) ||
+		!strings.HasSuffix(name, schema.suffix) {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(name[len(schema.prefix):len(name)-len(schema.suffix)], 10, 63)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return int64(seq), true
+}
+
+// TimestampNameSchema names log files using the time of rotation formatted by a Go time layout, like app-2006-01-02.log.
+// The layout must be parsable by time.Parse and should produce unique names for the rotation used.
+// Otherwise, existing files are reopened as defined by the OpenMode of the RollingFileAppender.
+type TimestampNameSchema struct {
+	layout string
+}
+
+// NewTimestampNameSchema creates a new TimestampNameSchema for given time layout.
+func NewTimestampNameSchema(layout string) *TimestampNameSchema {
+	return &TimestampNameSchema{layout: layout}
+}
+
+// Name returns the file name for the current time.
+func (schema *TimestampNameSchema) Name() string {
+	return schema.NameAt(time.Now())
+}
+
+// NameAt returns the file name for given time.
+func (schema *TimestampNameSchema) NameAt(t time.Time) string {
+	return t.Format(schema.layout)
+}
+
+// Match returns the timestamp in nanoseconds if the file name has been created by this schema.
+func (schema *TimestampNameSchema) Match(name string) (int64, bool) {
+	t, err := time.Parse(schema.layout, name)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return t.UnixNano(), true
+}
+
+// PatternNameSchema names log files using a pattern with placeholders, like {name}-{date}-{seq}.log.
+// The following placeholders are supported:
+//
+//  {name} the name passed on creation
+//  {date} the time of rotation formatted using the date layout
+//  {seq}  a sequence number starting at 1, which is reset when the date changes
+//  {host} the host name
+//  {pid}  the process ID
+//
+// The sequence number resumes from the highest number found in the log directory for the current date.
+// Files created by other processes on the same host are recognized as well, even if the pattern contains the process ID.
+type PatternNameSchema struct {
+	m          sync.Mutex
+	dir        string
+	pattern    string
+	name       string
+	dateLayout string
+	host       string
+	pid        int
+	regex      *regexp.Regexp
+	groups     []string
+	date       string
+	seq        int64
+	scanned    bool
+}
+
+// NewPatternNameSchema creates a new PatternNameSchema for given log directory, pattern, name and date layout.
+// The date layout defaults to 2006-01-02 if empty.
+func NewPatternNameSchema(dir, pattern, name, dateLayout string) (*PatternNameSchema, error) {
+	if dateLayout == "" {
+		dateLayout = "2006-01-02"
+	}
+
+	host, err := os.Hostname()
+
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &PatternNameSchema{dir: dir,
+		pattern:    pattern,
+		name:       name,
+		dateLayout: dateLayout,
+		host:       sanitizeFileName(host),
+		pid:        os.Getpid()}
+	var expr strings.Builder
+	expr.WriteRune('^')
+	last := 0
+
+	for _, loc := range patternPlaceholder.FindAllStringIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		placeholder := pattern[loc[0]:loc[1]]
+		schema.groups = append(schema.groups, placeholder)
+
+		switch placeholder {
+		case patternName:
+			expr.WriteString("(" + regexp.QuoteMeta(name) + ")")
+		case patternDate:
+			expr.WriteString("(.+?)")
+		case patternSeq:
+			expr.WriteString(`(\d+)`)
+		case patternHost:
+			expr.WriteString("(" + regexp.QuoteMeta(schema.host) + ")")
+		case patternPID:
+			expr.WriteString(`(\d+)`)
+		}
+
+		last = loc[1]
+	}
+
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	expr.WriteRune('$')
+	regex, err := regexp.Compile(expr.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	schema.regex = regex
+	return schema, nil
+}
+
+// Name returns the next file name for the current time.
+func (schema *PatternNameSchema) Name() string {
+	return schema.NameAt(time.Now())
+}
+
+// NameAt returns the next file name for given time.
+func (schema *PatternNameSchema) NameAt(t time.Time) string {
+	schema.m.Lock()
+	defer schema.m.Unlock()
+	date := ""
+
+	if strings.Contains(schema.pattern, patternDate) {
+		date = t.Format(schema.dateLayout)
+	}
+
+	if !schema.scanned || date != schema.date {
+		schema.scanned = true
+		schema.date = date
+		schema.seq = 0
+		scanFileNames(schema.dir, func(name string) bool {
+			fileDate, seq, ok := schema.match(name)
+
+			if ok && fileDate == date && seq > schema.seq {
+				schema.seq = seq
+			}
+
+			return ok
+		})
+	}
+
+	schema.seq++
+	return patternPlaceholder.ReplaceAllStringFunc(schema.pattern, func(placeholder string) string {
+		switch placeholder {
+		case patternName:
+			return schema.name
+		case patternDate:
+			return date
+		case patternSeq:
+			return strconv.FormatInt(schema.seq, 10)
+		case patternHost:
+			return schema.host
+		default:
+			return strconv.Itoa(schema.pid)
+		}
+	})
+}
+
+// Match returns whether the file name has been created by this schema.
+// Files are ordered by date first and sequence number second.
+func (schema *PatternNameSchema) Match(name string) (int64, bool) {
+	date, seq, ok := schema.match(name)
+
+	if !ok {
+		return 0, false
+	}
+
+	if date == "" {
+		return seq, true
+	}
+
+	t, err := time.Parse(schema.dateLayout, date)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return t.Unix()*seqPerDate + seq%seqPerDate, true
+}
+
+func (schema *PatternNameSchema) match(name string) (string, int64, bool) {
+	matches := schema.regex.FindStringSubmatch(name)
+
+	if matches == nil {
+		return "", 0, false
+	}
+
+	var date string
+	var seq int64
+
+	for i, placeholder := range schema.groups {
+		switch placeholder {
+		case patternDate:
+			date = matches[i+1]
+
+			if _, err := time.Parse(schema.dateLayout, date); err != nil {
+				return "", 0, false
+			}
+		case patternSeq:
+			n, err := strconv.ParseInt(matches[i+1], 10, 64)
+
+			if err != nil {
+				return "", 0, false
+			}
+
+			seq = n
+		}
+	}
+
+	return date, seq, true
+}
+
+// scanFileNames calls match for each file in the directory.
+// If the name doesn't match, it's called again without the extension, so that compressed files are recognized too.
+func scanFileNames(dir string, match func(string) bool) {
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		// the directory might not exist yet
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && !match(entry.Name()) {
+			match(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		}
+	}
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r < ' ' {
+			return '_'
+		}
+
+		return r
+	}, name)
+}
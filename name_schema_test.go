@@ -0,0 +1,168 @@
+package logbuch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSequenceNameSchema(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"app-3.log", "app-7.log.gz", "app-x.log", "other-9.log"} {
+		if err := os.WriteFile(filepath.Join("out", name), nil, 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema := NewSequenceNameSchema("out", "app-", ".log")
+
+	if name := schema.Name(); name != "app-8.log" {
+		t.Fatalf("Schema must resume from highest sequence number, but was: %v", name)
+	}
+
+	if name := schema.Name(); name != "app-9.log" {
+		t.Fatalf("Unexpected name: %v", name)
+	}
+
+	if seq, ok := schema.Match("app-42.log"); !ok || seq != 42 {
+		t.Fatalf("Name must match, but was: %v %v", seq, ok)
+	}
+
+	for _, name := range []string{"app-.log", "app--1.log", "app-1.txt", "foo-1.log"} {
+		if _, ok := schema.Match(name); ok {
+			t.Fatalf("Name must not match: %v", name)
+		}
+	}
+}
+
+func TestTimestampNameSchema(t *testing.T) {
+	schema := NewTimestampNameSchema("app-2006-01-02.log")
+	now := time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC)
+
+	if name := schema.NameAt(now); name != "app-2026-10-18.log" {
+		t.Fatalf("Unexpected name: %v", name)
+	}
+
+	first, ok := schema.Match("app-2026-10-18.log")
+
+	if !ok {
+		t.Fatal("Name must match")
+	}
+
+	second, ok := schema.Match("app-2026-10-19.log")
+
+	if !ok || second <= first {
+		t.Fatalf("Names must be ordered by time, but was: %v %v", first, second)
+	}
+
+	if _, ok := schema.Match("app-2026-13-19.log"); ok {
+		t.Fatal("Name must not match")
+	}
+}
+
+func TestPatternNameSchema(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	host, _ := os.Hostname()
+	host = sanitizeFileName(host)
+
+	for _, name := range []string{"app-2026-10-18-" + host + "-1-3.log.gz", "app-2026-10-17-" + host + "-1-5.log"} {
+		if err := os.WriteFile(filepath.Join("out", name), nil, 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema, err := NewPatternNameSchema("out", "{name}-{date}-{host}-{pid}-{seq}.log", "app", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+	day := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	expected := []string{
+		fmt.Sprintf("app-2026-10-18-%s-%s-4.log", host, pid),
+		fmt.Sprintf("app-2026-10-18-%s-%s-5.log", host, pid),
+		fmt.Sprintf("app-2026-10-19-%s-%s-1.log", host, pid),
+	}
+
+	for i, t2 := range []time.Time{day, day, day.AddDate(0, 0, 1)} {
+		if name := schema.NameAt(t2); name != expected[i] {
+			t.Fatalf("Expected name %v, but was: %v", expected[i], name)
+		}
+	}
+
+	older, ok := schema.Match("app-2026-10-17-" + host + "-123-5.log")
+
+	if !ok {
+		t.Fatal("Name created by another process must match")
+	}
+
+	newer, ok := schema.Match("app-2026-10-18-" + host + "-1-1.log")
+
+	if !ok || newer <= older {
+		t.Fatalf("Names must be ordered by date and sequence, but was: %v %v", older, newer)
+	}
+
+	for _, name := range []string{"app-2026-10-18-otherhost-1-1.log", "foo-2026-10-18-" + host + "-1-1.log", "app-invalid-" + host + "-1-1.log"} {
+		if _, ok := schema.Match(name); ok {
+			t.Fatalf("Name must not match: %v", name)
+		}
+	}
+}
+
+func TestPatternNameSchemaRollingFileAppender(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		schema, err := NewPatternNameSchema("out", "{name}-{seq}.log", "app", "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rfa, err := NewRollingFileAppender(2, 5, 1, "out", schema)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for j := 0; j < 3; j++ {
+			if _, err := rfa.Write([]byte("12345\n")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := rfa.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir, err := os.ReadDir("out")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dir) != 2 || dir[0].Name() != "app-7.log" || dir[1].Name() != "app-8.log" {
+		t.Fatalf("File limit must hold across restarts, but was: %v", dir)
+	}
+}
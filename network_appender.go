@@ -0,0 +1,549 @@
+package logbuch
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultNetworkBufferSize   = 1024 * 1024 // 1 MB
+	defaultNetworkDialTimeout  = time.Second * 5
+	defaultNetworkWriteTimeout = time.Second * 10
+	defaultNetworkMinBackoff   = time.Millisecond * 100
+	defaultNetworkMaxBackoff   = time.Second * 30
+	spoolFileExtension         = ".spool"
+)
+
+// NetworkAppenderOptions configures the NetworkAppender.
+type NetworkAppenderOptions struct {
+	// TLSConfig enables TLS if set.
+	TLSConfig *tls.Config
+
+	// DialTimeout is the timeout for connecting to the endpoint. Five seconds are used by default.
+	DialTimeout time.Duration
+
+	// WriteTimeout is the timeout for sending data to the endpoint. Ten seconds are used by default.
+	WriteTimeout time.Duration
+
+	// MinBackoff is the time to wait before reconnecting for the first time.
+	// It's doubled for each failed attempt up to MaxBackoff. By default, the backoff ranges from 100 milliseconds to 30 seconds.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// BufferSize is the maximum number of bytes kept in memory. One MB is used by default.
+	// If the buffer is full, data is written to the spool directory or dropped if there is none.
+	BufferSize int
+
+	// SpoolDir enables spooling data to disk when the buffer is full, for example because the endpoint is down.
+	// Spooled data is sent in order after reconnecting, also after restarting the application.
+	SpoolDir string
+
+	// MaxSpoolSize is the maximum number of bytes spooled to disk. Data exceeding the limit is dropped.
+	// The spool size is not limited by default.
+	MaxSpoolSize int64
+
+	// ErrorHandler is called for errors occurring in the background, like failing to connect.
+	// It must be safe for concurrent use and must not write to the appender.
+	ErrorHandler func(error)
+}
+
+// NetworkAppenderMetrics are the metrics of a NetworkAppender.
+type NetworkAppenderMetrics struct {
+	// BytesSent is the number of bytes sent to the endpoint.
+	BytesSent uint64
+
+	// Reconnects is the number of connections established after the connection has been lost.
+	Reconnects uint64
+
+	// DroppedBytes is the number of bytes dropped because the buffer and spool were full.
+	DroppedBytes uint64
+}
+
+// NetworkAppender is an io.Writer streaming log data to a TCP or TLS endpoint, like Fluent Bit or Vector.
+// Data is sent in the background. If the connection is lost, the NetworkAppender reconnects using exponential backoff.
+// It needs to be closed using the Close() method.
+type NetworkAppender struct {
+	// must be the first fields for 64-bit alignment of atomic operations
+	bytesSent    uint64
+	reconnects   uint64
+	droppedBytes uint64
+
+	network   string
+	address   string
+	options   NetworkAppenderOptions
+	buffer    []byte
+	segments  []spoolSegment
+	spoolSize int64
+	nextSeq   int64
+	pending   *networkChunk
+	conn      net.Conn
+	connDone  chan struct{}
+	notify    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	readers   sync.WaitGroup
+	m         sync.Mutex
+}
+
+// networkChunk is data taken from the buffer or spool to be sent.
+// The sequence number is assigned when the data leaves the buffer, so that the order is kept when it needs to be spooled.
+type networkChunk struct {
+	seq  int64
+	data []byte
+	path string
+}
+
+type spoolSegment struct {
+	seq  int64
+	path string
+	size int64
+}
+
+// NewNetworkAppender creates a new NetworkAppender for given network ("tcp", "tcp4", "tcp6" or "unix") and address.
+// The connection is established in the background, so that the endpoint doesn't need to be available.
+// Data spooled by a previous run is sent first.
+//
+//  appender, err := logbuch.NewNetworkAppender("tcp", "localhost:24224", logbuch.NetworkAppenderOptions{SpoolDir: "spool"})
+func NewNetworkAppender(network, address string, options NetworkAppenderOptions) (*NetworkAppender, error) {
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = defaultNetworkDialTimeout
+	}
+
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = defaultNetworkWriteTimeout
+	}
+
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaultNetworkMinBackoff
+	}
+
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = defaultNetworkMaxBackoff
+
+		if options.MaxBackoff < options.MinBackoff {
+			options.MaxBackoff = options.MinBackoff
+		}
+	}
+
+	if options.BufferSize <= 0 {
+		options.BufferSize = defaultNetworkBufferSize
+	}
+
+	appender := &NetworkAppender{network: network,
+		address: address,
+		options: options,
+		buffer:  make([]byte, 0, options.BufferSize),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{})}
+
+	if options.SpoolDir != "" {
+		if err := appender.loadSpool(); err != nil {
+			return nil, err
+		}
+	}
+
+	appender.wg.Add(1)
+	go appender.send()
+	appender.wakeUp()
+	return appender, nil
+}
+
+// Write adds given data to the buffer to be sent in the background.
+// If the buffer is full, the data is spooled to disk or dropped if spooling is disabled.
+func (appender *NetworkAppender) Write(p []byte) (int, error) {
+	appender.m.Lock()
+	defer appender.m.Unlock()
+
+	if len(appender.buffer)+len(p) > appender.options.BufferSize {
+		if appender.options.SpoolDir == "" {
+			atomic.AddUint64(&appender.droppedBytes, uint64(len(p)))
+			return len(p), nil
+		}
+
+		if err := appender.spoolBuffer(); err != nil {
+			return 0, err
+		}
+
+		// data larger than the buffer is spooled directly
+		if len(p) > appender.options.BufferSize {
+			if err := appender.spool(appender.nextSeq, p); err != nil {
+				return 0, err
+			}
+
+			appender.nextSeq++
+			appender.wakeUp()
+			return len(p), nil
+		}
+	}
+
+	appender.buffer = append(appender.buffer, p...)
+	appender.wakeUp()
+	return len(p), nil
+}
+
+// Metrics returns the metrics of the NetworkAppender.
+func (appender *NetworkAppender) Metrics() NetworkAppenderMetrics {
+	return NetworkAppenderMetrics{BytesSent: atomic.LoadUint64(&appender.bytesSent),
+		Reconnects:   atomic.LoadUint64(&appender.reconnects),
+		DroppedBytes: atomic.LoadUint64(&appender.droppedBytes)}
+}
+
+// Close sends the remaining data if the endpoint is connected and closes the connection.
+// Data which cannot be sent is spooled to disk or dropped if spooling is disabled.
+func (appender *NetworkAppender) Close() error {
+	appender.closeOnce.Do(func() {
+		close(appender.done)
+	})
+	appender.wg.Wait()
+	appender.m.Lock()
+	defer appender.m.Unlock()
+	var err error
+
+	if appender.pending != nil && appender.pending.path == "" {
+		if appender.options.SpoolDir != "" {
+			err = appender.spool(appender.pending.seq, appender.pending.data)
+		} else {
+			atomic.AddUint64(&appender.droppedBytes, uint64(len(appender.pending.data)))
+		}
+	}
+
+	appender.pending = nil
+
+	if e := appender.spoolBuffer(); e != nil && err == nil {
+		err = e
+	}
+
+	if appender.options.SpoolDir == "" {
+		atomic.AddUint64(&appender.droppedBytes, uint64(len(appender.buffer)))
+		appender.buffer = appender.buffer[:0]
+	}
+
+	if appender.conn != nil {
+		if e := appender.conn.Close(); e != nil && err == nil {
+			err = e
+		}
+
+		appender.conn = nil
+	}
+
+	appender.readers.Wait()
+	return err
+}
+
+// send sends the pending data, spool and buffer in this order until the appender is closed.
+// After the appender has been closed, the remaining data is sent as long as the connection is up.
+func (appender *NetworkAppender) send() {
+	defer appender.wg.Done()
+	backoff := appender.options.MinBackoff
+	connected := false
+	triedOnClose := false
+
+	for {
+		chunk, err := appender.next()
+
+		if err != nil {
+			appender.reportErr(err)
+			continue
+		}
+
+		if chunk == nil {
+			select {
+			case <-appender.done:
+				return
+			case <-appender.notify:
+				continue
+			}
+		}
+
+		if appender.conn != nil {
+			select {
+			case <-appender.connDone:
+				appender.disconnect(errors.New("connection closed by remote host"))
+			default:
+			}
+		}
+
+		if appender.conn == nil {
+			// try to connect once to send the remaining data after the appender has been closed
+			if appender.isClosed() {
+				if triedOnClose {
+					return
+				}
+
+				triedOnClose = true
+			}
+
+			if err := appender.connect(); err != nil {
+				appender.reportErr(err)
+
+				if !appender.wait(backoff) && triedOnClose {
+					return
+				}
+
+				backoff *= 2
+
+				if backoff > appender.options.MaxBackoff {
+					backoff = appender.options.MaxBackoff
+				}
+
+				continue
+			}
+
+			if connected {
+				atomic.AddUint64(&appender.reconnects, 1)
+			}
+
+			connected = true
+			backoff = appender.options.MinBackoff
+		}
+
+		if err := appender.conn.SetWriteDeadline(time.Now().Add(appender.options.WriteTimeout)); err != nil {
+			appender.disconnect(err)
+			continue
+		}
+
+		n, err := appender.conn.Write(chunk.data)
+		atomic.AddUint64(&appender.bytesSent, uint64(n))
+
+		if err != nil {
+			// the unsent rest is sent again after reconnecting
+			chunk.data = chunk.data[n:]
+			appender.disconnect(err)
+			continue
+		}
+
+		if err := appender.sent(chunk); err != nil {
+			appender.reportErr(err)
+		}
+	}
+}
+
+// next returns the data to send next or nil if there is none.
+func (appender *NetworkAppender) next() (*networkChunk, error) {
+	appender.m.Lock()
+	defer appender.m.Unlock()
+
+	if appender.pending != nil {
+		return appender.pending, nil
+	}
+
+	if len(appender.segments) > 0 {
+		segment := appender.segments[0]
+		data, err := os.ReadFile(segment.path)
+
+		if err != nil {
+			// the segment cannot be sent, so it's dropped
+			appender.segments = appender.segments[1:]
+			appender.spoolSize -= segment.size
+			atomic.AddUint64(&appender.droppedBytes, uint64(segment.size))
+			return nil, err
+		}
+
+		appender.pending = &networkChunk{seq: segment.seq, data: data, path: segment.path}
+		return appender.pending, nil
+	}
+
+	if len(appender.buffer) > 0 {
+		data := make([]byte, len(appender.buffer))
+		copy(data, appender.buffer)
+		appender.buffer = appender.buffer[:0]
+		appender.pending = &networkChunk{seq: appender.nextSeq, data: data}
+		appender.nextSeq++
+		return appender.pending, nil
+	}
+
+	return nil, nil
+}
+
+// sent removes the chunk after it has been sent successfully.
+func (appender *NetworkAppender) sent(chunk *networkChunk) error {
+	appender.m.Lock()
+	defer appender.m.Unlock()
+	appender.pending = nil
+
+	if chunk.path == "" {
+		return nil
+	}
+
+	appender.spoolSize -= appender.segments[0].size
+	appender.segments = appender.segments[1:]
+
+	if err := os.Remove(chunk.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (appender *NetworkAppender) connect() error {
+	dialer := &net.Dialer{Timeout: appender.options.DialTimeout}
+	var conn net.Conn
+	var err error
+
+	if appender.options.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, appender.network, appender.address, appender.options.TLSConfig)
+	} else {
+		conn, err = dialer.Dial(appender.network, appender.address)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the remote host doesn't send data, but reading detects when it closes the connection
+	connDone := make(chan struct{})
+	appender.readers.Add(1)
+
+	go func() {
+		defer appender.readers.Done()
+		io.Copy(io.Discard, conn)
+		close(connDone)
+	}()
+
+	appender.m.Lock()
+	appender.conn = conn
+	appender.connDone = connDone
+	appender.m.Unlock()
+	return nil
+}
+
+func (appender *NetworkAppender) disconnect(err error) {
+	appender.m.Lock()
+	appender.conn.Close()
+	appender.conn = nil
+	appender.m.Unlock()
+	appender.reportErr(err)
+}
+
+// wait waits for given duration and returns false if the appender has been closed in the meantime.
+func (appender *NetworkAppender) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-appender.done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (appender *NetworkAppender) isClosed() bool {
+	select {
+	case <-appender.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (appender *NetworkAppender) wakeUp() {
+	select {
+	case appender.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (appender *NetworkAppender) reportErr(err error) {
+	if appender.options.ErrorHandler != nil {
+		appender.options.ErrorHandler(err)
+	}
+}
+
+// spoolBuffer writes the buffer to the spool directory, if enabled.
+func (appender *NetworkAppender) spoolBuffer() error {
+	if appender.options.SpoolDir == "" || len(appender.buffer) == 0 {
+		return nil
+	}
+
+	if err := appender.spool(appender.nextSeq, appender.buffer); err != nil {
+		return err
+	}
+
+	appender.nextSeq++
+	appender.buffer = appender.buffer[:0]
+	return nil
+}
+
+// spool writes given data to a new segment in the spool directory.
+// Data exceeding the maximum spool size is dropped.
+func (appender *NetworkAppender) spool(seq int64, data []byte) error {
+	size := int64(len(data))
+
+	if appender.options.MaxSpoolSize > 0 && appender.spoolSize+size > appender.options.MaxSpoolSize {
+		atomic.AddUint64(&appender.droppedBytes, uint64(size))
+		return nil
+	}
+
+	path := filepath.Join(appender.options.SpoolDir, fmt.Sprintf("%020d%s", seq, spoolFileExtension))
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0664); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	appender.segments = append(appender.segments, spoolSegment{seq, path, size})
+	appender.spoolSize += size
+	return nil
+}
+
+// loadSpool reads the segments spooled by a previous run.
+func (appender *NetworkAppender) loadSpool() error {
+	if err := os.MkdirAll(appender.options.SpoolDir, 0774); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(appender.options.SpoolDir)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, spoolFileExtension) {
+			continue
+		}
+
+		seq, err := strconv.ParseInt(strings.TrimSuffix(name, spoolFileExtension), 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		appender.segments = append(appender.segments, spoolSegment{seq, filepath.Join(appender.options.SpoolDir, name), info.Size()})
+		appender.spoolSize += info.Size()
+
+		if seq >= appender.nextSeq {
+			appender.nextSeq = seq + 1
+		}
+	}
+
+	sort.Slice(appender.segments, func(i, j int) bool {
+		return appender.segments[i].seq < appender.segments[j].seq
+	})
+	return nil
+}
@@ -0,0 +1,322 @@
+package logbuch
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testNetworkServer struct {
+	listener net.Listener
+	data     []byte
+	conns    []net.Conn
+	m        sync.Mutex
+}
+
+func newTestNetworkServer(t *testing.T, listener net.Listener) *testNetworkServer {
+	server := &testNetworkServer{listener: listener}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			server.m.Lock()
+			server.conns = append(server.conns, conn)
+			server.m.Unlock()
+
+			go func() {
+				buffer := make([]byte, 1024)
+
+				for {
+					n, err := conn.Read(buffer)
+					server.m.Lock()
+					server.data = append(server.data, buffer[:n]...)
+					server.m.Unlock()
+
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return server
+}
+
+func (server *testNetworkServer) waitFor(t *testing.T, expected string) {
+	deadline := time.Now().Add(time.Second * 5)
+
+	for time.Now().Before(deadline) {
+		if server.received() == expected {
+			return
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	t.Fatalf("Expected %q, but was: %q", expected, server.received())
+}
+
+func (server *testNetworkServer) received() string {
+	server.m.Lock()
+	defer server.m.Unlock()
+	return string(server.data)
+}
+
+func (server *testNetworkServer) closeConns() {
+	server.m.Lock()
+	defer server.m.Unlock()
+
+	for _, conn := range server.conns {
+		conn.Close()
+	}
+
+	server.conns = nil
+}
+
+func (server *testNetworkServer) close() {
+	server.listener.Close()
+	server.closeConns()
+}
+
+func TestNetworkAppender(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestNetworkServer(t, listener)
+	defer server.close()
+	appender, err := NewNetworkAppender("tcp", listener.Addr().String(), NetworkAppenderOptions{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(appender, appender)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.Info("first")
+	l.Error("second")
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[INFO ] first\n[ERROR] second\n"
+	server.waitFor(t, expected)
+
+	if metrics := appender.Metrics(); metrics.BytesSent != uint64(len(expected)) || metrics.DroppedBytes != 0 {
+		t.Fatalf("Unexpected metrics: %v", metrics)
+	}
+}
+
+func TestNetworkAppenderReconnectSpool(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	address := unusedAddress(t)
+	appender, err := NewNetworkAppender("tcp", address, NetworkAppenderOptions{
+		MinBackoff: time.Millisecond * 10,
+		MaxBackoff: time.Millisecond * 20,
+		BufferSize: 10,
+		SpoolDir:   "out/spool",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected strings.Builder
+
+	for i := 0; i < 10; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		expected.WriteString(line)
+
+		if _, err := appender.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestNetworkServer(t, listener)
+	defer server.close()
+	server.waitFor(t, expected.String())
+
+	// force a reconnect, which is detected before sending new data
+	server.closeConns()
+	time.Sleep(time.Millisecond * 50)
+
+	for i := 10; i < 20; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		expected.WriteString(line)
+
+		if _, err := appender.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(time.Millisecond * 5)
+	}
+
+	server.waitFor(t, expected.String())
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if metrics := appender.Metrics(); metrics.Reconnects == 0 || metrics.DroppedBytes != 0 {
+		t.Fatalf("Unexpected metrics: %v", metrics)
+	}
+
+	entries, err := os.ReadDir("out/spool")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("Spool must be empty, but was: %v", entries)
+	}
+}
+
+func TestNetworkAppenderSpoolReplay(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	address := unusedAddress(t)
+	options := NetworkAppenderOptions{MinBackoff: time.Millisecond * 10, BufferSize: 10, SpoolDir: "out/spool"}
+	appender, err := NewNetworkAppender("tcp", address, options)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected strings.Builder
+
+	for i := 0; i < 5; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		expected.WriteString(line)
+
+		if _, err := appender.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestNetworkServer(t, listener)
+	defer server.close()
+	appender, err = NewNetworkAppender("tcp", address, options)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := appender.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected.WriteString("new\n")
+	server.waitFor(t, expected.String())
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNetworkAppenderDrop(t *testing.T) {
+	appender, err := NewNetworkAppender("tcp", unusedAddress(t), NetworkAppenderOptions{MinBackoff: time.Millisecond * 10, BufferSize: 10})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := appender.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if metrics := appender.Metrics(); metrics.DroppedBytes != 30 || metrics.BytesSent != 0 {
+		t.Fatalf("All data must have been dropped, but was: %v", metrics)
+	}
+}
+
+func TestNetworkAppenderTLS(t *testing.T) {
+	// use the certificate of a test server to start a TLS listener
+	httpServer := httptest.NewTLSServer(http.NotFoundHandler())
+	clientConfig := httpServer.Client().Transport.(*http.Transport).TLSClientConfig
+	serverConfig := httpServer.TLS
+	httpServer.Close()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestNetworkServer(t, listener)
+	defer server.close()
+	appender, err := NewNetworkAppender("tcp", listener.Addr().String(), NetworkAppenderOptions{TLSConfig: clientConfig})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := io.WriteString(appender, "secure\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.waitFor(t, "secure\n")
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func unusedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := listener.Addr().String()
+
+	if err := listener.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return address
+}
@@ -0,0 +1,161 @@
+package logbuch
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Reopener is implemented by outputs which can reopen the file they write to.
+// This is required if log files are moved by external tools like logrotate.
+type Reopener interface {
+	// Reopen closes the current file and opens the file at the original path again.
+	Reopen() error
+}
+
+// ReopenableFileWriter is an io.Writer appending to a file, which can be reopened on demand.
+// Use it instead of an os.File if the log file is rotated by an external tool.
+type ReopenableFileWriter struct {
+	path string
+	file *os.File
+	m    sync.Mutex
+}
+
+// NewReopenableFileWriter opens the file at given path for appending. The file is created if required.
+// The ReopenableFileWriter needs to be closed using the Close() method.
+func NewReopenableFileWriter(path string) (*ReopenableFileWriter, error) {
+	f, err := openAppend(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReopenableFileWriter{path: path, file: f}, nil
+}
+
+// Write writes given data to the file.
+func (writer *ReopenableFileWriter) Write(p []byte) (int, error) {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	return writer.file.Write(p)
+}
+
+// Reopen closes the file and opens the file at the original path again.
+// If the file has been moved, a new one is created.
+func (writer *ReopenableFileWriter) Reopen() error {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	f, err := openAppend(writer.path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := writer.file.Close(); err != nil {
+		f.Close()
+		return err
+	}
+
+	writer.file = f
+	return nil
+}
+
+// Close closes the file.
+func (writer *ReopenableFileWriter) Close() error {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	return writer.file.Close()
+}
+
+// Reopen flushes the buffer to the active log file, closes it and opens the file at the same path again.
+// If the file has been moved, a new one is created. Existing files are appended to, regardless of the OpenMode.
+// The log file keeps counting towards the file limit and is not compressed.
+// The new file is opened before closing the current one, so that the appender keeps working if opening fails.
+func (appender *RollingFileAppender) Reopen() error {
+	appender.m.Lock()
+	defer appender.unlock()
+	n, err := appender.currentFile.Write(appender.buffer)
+
+	if err != nil {
+		return joinErrors(appender.backgroundErr(), err)
+	}
+
+	appender.buffer = appender.buffer[:0]
+	appender.unsynced = appender.unsynced || n > 0
+
+	if err := appender.sync(); err != nil {
+		return joinErrors(appender.backgroundErr(), err)
+	}
+
+	f, err := openAppend(appender.currentFile.Name())
+
+	if err != nil {
+		return joinErrors(appender.backgroundErr(), err)
+	}
+
+	info, err := f.Stat()
+
+	if err != nil {
+		f.Close()
+		return joinErrors(appender.backgroundErr(), err)
+	}
+
+	// the new file is used even if closing the old one fails, as its state is unknown
+	err = appender.currentFile.Close()
+	appender.currentFile = f
+	appender.currentFileSize = int(info.Size())
+	appender.unsynced = false
+	err = joinErrors(err, appender.syncDir())
+	return joinErrors(appender.backgroundErr(), err)
+}
+
+// ReopenOnSignal reopens all given Reopeners when the process receives SIGHUP or SIGUSR1.
+// On Windows, only SIGHUP is supported. Platforms without these signals, like Plan 9, never reopen.
+// Errors are passed to the error handler, which can be nil to ignore them.
+// The returned function stops listening for signals.
+//
+//  appender, _ := logbuch.NewRollingFileAppender(...)
+//  stop := logbuch.ReopenOnSignal(nil, appender)
+//  defer stop()
+func ReopenOnSignal(errorHandler func(error), reopeners ...Reopener) func() {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// passing no signals to Notify would relay all of them
+	if len(reopenSignals) > 0 {
+		signal.Notify(c, reopenSignals...)
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-c:
+				for _, reopener := range reopeners {
+					if err := reopener.Reopen(); err != nil && errorHandler != nil {
+						errorHandler(err)
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0664)
+}
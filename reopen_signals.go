@@ -0,0 +1,11 @@
+//go:build !windows && !plan9 && !js && !wasip1
+// +build !windows,!plan9,!js,!wasip1
+
+package logbuch
+
+import (
+	"os"
+	"syscall"
+)
+
+var reopenSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
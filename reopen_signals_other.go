@@ -0,0 +1,11 @@
+//go:build plan9 || js || wasip1
+// +build plan9 js wasip1
+
+package logbuch
+
+import (
+	"os"
+)
+
+// reopening on signal is not supported on this platform
+var reopenSignals []os.Signal
@@ -0,0 +1,8 @@
+package logbuch
+
+import (
+	"os"
+	"syscall"
+)
+
+var reopenSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,189 @@
+package logbuch
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenableFileWriter(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := NewReopenableFileWriter("out/app.log")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := writer.Write([]byte("old\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename("out/app.log", "out/app.log.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := writer.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileContent(t, "out/app.log.1", "old\n")
+	assertFileContent(t, "out/app.log", "new\n")
+}
+
+func TestRollingFileAppenderReopen(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(2, 100, 100, "out", &testNameSchema{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rfa.Write([]byte("old\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename("out/1_log.txt", "out/moved.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rfa.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileContent(t, "out/moved.txt", "old\n")
+	assertFileContent(t, "out/1_log.txt", "new\n")
+}
+
+func TestRollingFileAppenderReopenError(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(2, 100, 100, "out", &testNameSchema{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename("out/1_log.txt", "out/moved.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a directory cannot be opened for writing
+	if err := os.Mkdir("out/1_log.txt", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Reopen(); err == nil {
+		t.Fatal("Reopen must fail")
+	}
+
+	if _, err := rfa.Write([]byte("kept\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatalf("The current file must be kept open, but was: %v", err)
+	}
+
+	assertFileContent(t, "out/moved.txt", "kept\n")
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Sending signals is not supported on Windows")
+	}
+
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := NewReopenableFileWriter("out/app.log")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := ReopenOnSignal(func(err error) {
+		t.Errorf("Reopen must not fail, but was: %v", err)
+	}, writer)
+	defer stop()
+
+	if err := os.Rename("out/app.log", "out/app.log.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat("out/app.log"); err == nil {
+			break
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if _, err := writer.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	stop()
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileContent(t, "out/app.log", "new\n")
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != expected {
+		t.Fatalf("File %v must contain %q, but was: %q", path, expected, string(content))
+	}
+}
@@ -0,0 +1,165 @@
+package logbuch
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	defaultRetentionCheck = time.Minute
+)
+
+// DeleteReason is the reason a log file has been deleted by the RollingFileAppender.
+type DeleteReason int
+
+const (
+	// DeleteFileLimit is used for files deleted because the maximum number of files has been exceeded.
+	DeleteFileLimit DeleteReason = iota
+
+	// DeleteMaxAge is used for files deleted because they are older than the maximum age.
+	DeleteMaxAge
+
+	// DeleteMaxTotalSize is used for files deleted because the maximum total size has been exceeded.
+	DeleteMaxTotalSize
+)
+
+type deletedFile struct {
+	path   string
+	reason DeleteReason
+}
+
+// String returns the name of the reason.
+func (reason DeleteReason) String() string {
+	switch reason {
+	case DeleteFileLimit:
+		return "file limit"
+	case DeleteMaxAge:
+		return "max age"
+	case DeleteMaxTotalSize:
+		return "max total size"
+	default:
+		return "unknown"
+	}
+}
+
+// MaxAge makes the RollingFileAppender delete log files last modified longer ago than the given duration.
+// The active log file is never deleted.
+// Retention is evaluated on rotation and periodically once a minute, together with the file limit.
+func MaxAge(maxAge time.Duration) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.maxAge = maxAge
+	}
+}
+
+// MaxTotalSize makes the RollingFileAppender delete the oldest log files when the total size of all log files in bytes exceeds the limit.
+// Compressed files count with their compressed size. The active log file is never deleted.
+// Retention is evaluated on rotation and periodically once a minute, together with the file limit.
+func MaxTotalSize(bytes int64) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.maxTotalSize = bytes
+	}
+}
+
+// OnDelete sets a function called for each log file deleted by the RollingFileAppender, together with the reason.
+// It's called after the appender has been unlocked and must be safe for concurrent use.
+func OnDelete(handler func(path string, reason DeleteReason)) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.deleteHandler = handler
+	}
+}
+
+func (appender *RollingFileAppender) hasRetentionPolicy() bool {
+	return appender.maxAge > 0 || appender.maxTotalSize > 0
+}
+
+// applyRetention deletes log files exceeding the maximum age and total size, except for the active one.
+func (appender *RollingFileAppender) applyRetention() error {
+	if !appender.hasRetentionPolicy() || len(appender.fileNames) < 2 {
+		return nil
+	}
+
+	files := appender.fileNames[:len(appender.fileNames)-1]
+	retained := make([]string, 0, len(appender.fileNames))
+	sizes := make([]int64, 0, len(appender.fileNames))
+	deadline := appender.now().Add(-appender.maxAge)
+	var totalSize int64
+
+	for _, path := range files {
+		info, err := appender.statFile(path)
+
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if appender.maxAge > 0 && info.ModTime().Before(deadline) {
+			if err := appender.removeFile(path, DeleteMaxAge); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		retained = append(retained, path)
+		sizes = append(sizes, info.Size())
+		totalSize += info.Size()
+	}
+
+	if appender.maxTotalSize > 0 {
+		totalSize += int64(appender.currentFileSize + len(appender.buffer))
+
+		for len(retained) > 0 && totalSize > appender.maxTotalSize {
+			if err := appender.removeFile(retained[0], DeleteMaxTotalSize); err != nil {
+				return err
+			}
+
+			totalSize -= sizes[0]
+			retained = retained[1:]
+			sizes = sizes[1:]
+		}
+	}
+
+	appender.fileNames = append(retained, appender.fileNames[len(appender.fileNames)-1])
+	return nil
+}
+
+// statFile returns the file info for a log file or its compressed version.
+func (appender *RollingFileAppender) statFile(path string) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+
+	if os.IsNotExist(err) && appender.compressor != nil {
+		return os.Stat(path + appender.compressor.Extension())
+	}
+
+	return info, err
+}
+
+// applyRetentionPeriodically applies the retention policies in the background, even if nothing is written.
+func (appender *RollingFileAppender) applyRetentionPeriodically() {
+	defer appender.wg.Done()
+	ticker := time.NewTicker(appender.retentionCheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-appender.done:
+			return
+		case <-ticker.C:
+			appender.m.Lock()
+			files := len(appender.fileNames)
+			err := appender.applyRetention()
+
+			// make the deletion durable, like on rotation
+			if err == nil && len(appender.fileNames) != files {
+				err = appender.syncDir()
+			}
+
+			appender.unlock()
+
+			if err != nil {
+				appender.reportErr(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package logbuch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testDeleteHandler struct {
+	m       sync.Mutex
+	deleted map[string]DeleteReason
+}
+
+func (handler *testDeleteHandler) handle(path string, reason DeleteReason) {
+	handler.m.Lock()
+	defer handler.m.Unlock()
+	handler.deleted[filepath.Base(path)] = reason
+}
+
+func (handler *testDeleteHandler) get(name string) (DeleteReason, bool) {
+	handler.m.Lock()
+	defer handler.m.Unlock()
+	reason, ok := handler.deleted[name]
+	return reason, ok
+}
+
+func TestRollingFileAppenderMaxAge(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, age := range []time.Duration{time.Hour * 72, time.Hour * 48, time.Hour} {
+		path := filepath.Join("out", fmt.Sprintf("%d_log.txt", i+1))
+
+		if err := os.WriteFile(path, []byte("old\n"), 0664); err != nil {
+			t.Fatal(err)
+		}
+
+		modTime := time.Now().Add(-age)
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := &testDeleteHandler{deleted: make(map[string]DeleteReason)}
+	schema := &testMatchingNameSchema{testNameSchema{counter: 3}}
+	rfa, err := NewRollingFileAppender(10, 100, 10, "out", schema, MaxAge(time.Hour*24), OnDelete(handler.handle))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// deletions are reported after the lock has been released
+	if err := rfa.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"1_log.txt", "2_log.txt"} {
+		if reason, ok := handler.get(name); !ok || reason != DeleteMaxAge {
+			t.Fatalf("File %v must have been deleted due to its age, but was: %v %v", name, ok, reason)
+		}
+
+		if _, err := os.Stat(filepath.Join("out", name)); !os.IsNotExist(err) {
+			t.Fatalf("File %v must not exist, but was: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"3_log.txt", "4_log.txt"} {
+		if _, err := os.Stat(filepath.Join("out", name)); err != nil {
+			t.Fatalf("File %v must exist, but was: %v", name, err)
+		}
+	}
+}
+
+func TestRollingFileAppenderMaxTotalSize(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &testDeleteHandler{deleted: make(map[string]DeleteReason)}
+	rfa, err := NewRollingFileAppender(10, 5, 1, "out", &testNameSchema{}, MaxTotalSize(13), OnDelete(handler.handle))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := rfa.Write([]byte(fmt.Sprintf("%d2345\n", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.ReadDir("out")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dir) != 3 || dir[0].Name() != "4_log.txt" || dir[1].Name() != "5_log.txt" || dir[2].Name() != "6_log.txt" {
+		t.Fatalf("Oldest files must have been deleted, but was: %v", dir)
+	}
+
+	expected := map[string]DeleteReason{"1_log.txt": DeleteMaxTotalSize, "2_log.txt": DeleteMaxTotalSize, "3_log.txt": DeleteMaxTotalSize}
+
+	for name, expectedReason := range expected {
+		if reason, ok := handler.get(name); !ok || reason != expectedReason {
+			t.Fatalf("File %v must have been deleted for reason %v, but was: %v %v", name, expectedReason, ok, reason)
+		}
+	}
+}
+
+func TestRollingFileAppenderRetentionPeriodically(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(10, 5, 1, "out", &testNameSchema{}, MaxAge(time.Millisecond*50), func(appender *RollingFileAppender) {
+		appender.retentionCheck = time.Millisecond * 10
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := os.Stat("out/1_log.txt"); err != nil {
+		t.Fatalf("Rotated file must exist, but was: %v", err)
+	}
+
+	time.Sleep(time.Millisecond * 150)
+
+	if _, err := os.Stat("out/1_log.txt"); !os.IsNotExist(err) {
+		t.Fatalf("Rotated file must have been deleted in background, but was: %v", err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,15 +2,32 @@ package logbuch
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 const (
 	defaultFiles      = 1
 	defaultFileSize   = 1024 * 1024 * 5 // 5 MB
 	defaultBufferSize = 4096            // 4 KB
+	maxSkippedNames   = 1000
+)
+
+// OpenMode defines how the RollingFileAppender opens log files which already exist.
+type OpenMode int
+
+const (
+	// OpenAppend appends to existing log files. Their size counts towards the file size limit.
+	OpenAppend OpenMode = iota
+
+	// OpenTruncate truncates existing log files.
+	OpenTruncate
+
+	// OpenSkip leaves existing log files untouched and uses the next name returned by the NameSchema instead.
+	OpenSkip
 )
 
 // NameSchema is an interface to generate log file names.
@@ -20,28 +37,87 @@ type NameSchema interface {
 	Name() string
 }
 
+// TimeNameSchema is an optional interface for NameSchemas using the time of rotation to generate log file names.
+// If the NameSchema passed to the RollingFileAppender implements it, NameAt() is called instead of Name().
+type TimeNameSchema interface {
+	NameSchema
+
+	// NameAt returns the next file name used to store log data for given rotation time.
+	// For time based rotation, the time is the start of the period in the configured time zone.
+	NameAt(time.Time) string
+}
+
+// RollingFileAppenderOption configures optional features of the RollingFileAppender.
+type RollingFileAppenderOption func(*RollingFileAppender)
+
+// OpenExisting sets the OpenMode used if the NameSchema returns the name of an existing log file.
+// By default, the RollingFileAppender appends to existing files.
+func OpenExisting(mode OpenMode) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.openMode = mode
+	}
+}
+
+// OnError sets a function called for errors occurring in background tasks of the RollingFileAppender,
+// like rotating or compressing log files. The handler must be safe for concurrent use and must not write to the appender.
+// Without a handler, the error is returned by the next call to Write, Flush or Close.
+func OnError(handler func(error)) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.errorHandler = handler
+	}
+}
+
 // RollingFileAppender is a manager for rolling log files.
 // It needs to be closed using the Close() method.
 type RollingFileAppender struct {
-	files           int
-	fileSize        int
-	fileName        NameSchema
-	fileDir         string
-	buffer          []byte
-	maxBufferSize   int
-	currentFile     *os.File
-	currentFileSize int
-	fileNames       []string
-	m               sync.Mutex
+	files            int
+	fileSize         int
+	fileName         NameSchema
+	fileDir          string
+	buffer           []byte
+	maxBufferSize    int
+	currentFile      *os.File
+	currentFileSize  int
+	fileNames        []string
+	openMode         OpenMode
+	rotationInterval time.Duration
+	rotationPeriod   RotationPeriod
+	location         *time.Location
+	nextRotation     time.Time
+	now              func() time.Time
+	compressor       Compressor
+	maxAge           time.Duration
+	maxTotalSize     int64
+	retentionCheck   time.Duration
+	deleteHandler    func(string, DeleteReason)
+	flushInterval    time.Duration
+	flushLevel       int
+	flushOnLevel     bool
+	syncMode         SyncMode
+	syncInterval     time.Duration
+	unsynced         bool
+	symlink          string
+	symlinkErrs      []error
+	deleted          []deletedFile
+	compressing      map[string]*deletedFile
+	errorHandler     func(error)
+	err              error
+	done             chan struct{}
+	closeOnce        sync.Once
+	wg               sync.WaitGroup
+	tasks            sync.WaitGroup
+	m                sync.Mutex
 }
 
 // NewRollingFileAppender creates a new RollingFileAppender.
 // If you pass values below or equal to 0 for files, size or bufferSize, default values will be used.
 // The file output directory is created if required and can be left empty to use the current directory.
 // The filename schema is required. Note that the rolling file appender uses the filename schema you provide,
-// so if it returns the same name on each call, it will overwrite the existing log file.
-// The RollingFileAppender won't clean the log directory on startup. Old log files will stay in place.
-func NewRollingFileAppender(files, size, bufferSize int, dir string, filename NameSchema) (*RollingFileAppender, error) {
+// so if it returns the same name on each call, it will reopen the existing log file as defined by the OpenMode (appending by default).
+// The RollingFileAppender won't clean the log directory on startup, unless the filename schema implements the MatchingNameSchema interface.
+// In that case, existing log files are included in the file limit. Otherwise, old log files will stay in place.
+// Optional features, like time based rotation, can be enabled by passing options.
+func NewRollingFileAppender(files, size, bufferSize int, dir string, filename NameSchema, options ...RollingFileAppenderOption) (*RollingFileAppender, error) {
 	if files <= 0 {
 		files = defaultFiles
 	}
@@ -63,17 +139,49 @@ func NewRollingFileAppender(files, size, bufferSize int, dir string, filename Na
 	}
 
 	appender := &RollingFileAppender{files: files,
-		fileSize:      size,
-		fileName:      filename,
-		fileDir:       dir,
-		buffer:        make([]byte, 0, bufferSize),
-		maxBufferSize: bufferSize,
-		fileNames:     make([]string, 0, files)}
-
-	if err := appender.nextFile(); err != nil {
+		fileSize:       size,
+		fileName:       filename,
+		fileDir:        dir,
+		buffer:         make([]byte, 0, bufferSize),
+		maxBufferSize:  bufferSize,
+		fileNames:      make([]string, 0, files),
+		location:       time.Local,
+		now:            time.Now,
+		retentionCheck: defaultRetentionCheck,
+		done:           make(chan struct{})}
+
+	for _, option := range options {
+		option(appender)
+	}
+
+	if err := appender.adoptFiles(); err != nil {
+		return nil, err
+	}
+
+	if err := appender.nextFile(appender.updateRotation()); err != nil {
 		return nil, err
 	}
 
+	if appender.hasTimeRotation() {
+		appender.wg.Add(1)
+		go appender.rotateOnSchedule()
+	}
+
+	if appender.hasRetentionPolicy() {
+		appender.wg.Add(1)
+		go appender.applyRetentionPeriodically()
+	}
+
+	if appender.flushInterval > 0 {
+		appender.wg.Add(1)
+		go appender.flushPeriodically()
+	}
+
+	if appender.syncMode == SyncOnInterval && appender.syncInterval > 0 {
+		appender.wg.Add(1)
+		go appender.syncPeriodically()
+	}
+
 	return appender, nil
 }
 
@@ -82,7 +190,38 @@ func NewRollingFileAppender(files, size, bufferSize int, dir string, filename Na
 // If you want the data to be persisted, call Flush().
 func (appender *RollingFileAppender) Write(p []byte) (n int, err error) {
 	appender.m.Lock()
-	defer appender.m.Unlock()
+	defer appender.unlock()
+	return appender.write(p)
+}
+
+// WriteLevel writes given data to the rolling log files like Write does.
+// If the level is greater than or equal to the level set using FlushOnLevel, the data is flushed immediately.
+// This method is called by the Logger instead of Write.
+func (appender *RollingFileAppender) WriteLevel(level int, p []byte) (int, error) {
+	appender.m.Lock()
+	defer appender.unlock()
+	n, err := appender.write(p)
+
+	if err != nil {
+		return n, err
+	}
+
+	if appender.flushOnLevel && level >= appender.flushLevel {
+		if err := appender.flush(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// write buffers the data. Errors of background tasks are returned after the data has been accepted, so that it isn't lost.
+func (appender *RollingFileAppender) write(p []byte) (int, error) {
+	if appender.hasTimeRotation() && !appender.now().Before(appender.nextRotation) {
+		if err := appender.rotate(); err != nil {
+			return 0, err
+		}
+	}
 
 	if len(appender.buffer) >= appender.maxBufferSize {
 		if err := appender.flush(); err != nil {
@@ -91,26 +230,39 @@ func (appender *RollingFileAppender) Write(p []byte) (n int, err error) {
 	}
 
 	appender.buffer = append(appender.buffer, p...)
-	return len(p), nil
+	return len(p), appender.backgroundErr()
 }
 
 // Flush writes all log data currently in buffer into the currently active log file.
 func (appender *RollingFileAppender) Flush() error {
 	appender.m.Lock()
-	defer appender.m.Unlock()
-	return appender.flush()
+	defer appender.unlock()
+	return joinErrors(appender.backgroundErr(), appender.flush())
 }
 
-// Close flushes the log data and closes all open file handlers.
+// Close flushes the log data, stops background tasks and closes all open file handlers.
+// It waits for rotated files to be compressed.
 func (appender *RollingFileAppender) Close() error {
+	appender.closeOnce.Do(func() {
+		close(appender.done)
+	})
+	appender.wg.Wait()
+	err := appender.close()
+	appender.tasks.Wait()
 	appender.m.Lock()
-	defer appender.m.Unlock()
-
-	if err := appender.flush(); err != nil {
-		return err
-	}
+	err = joinErrors(err, appender.backgroundErr())
+	appender.m.Unlock()
+	return err
+}
 
-	return appender.currentFile.Close()
+// close flushes, syncs and closes the current file, even if one of the steps fails, so that no data is lost and the file handler doesn't leak.
+func (appender *RollingFileAppender) close() error {
+	appender.m.Lock()
+	defer appender.unlock()
+	err := appender.backgroundErr()
+	err = joinErrors(err, appender.flush())
+	err = joinErrors(err, appender.sync())
+	return joinErrors(err, appender.currentFile.Close())
 }
 
 func (appender *RollingFileAppender) flush() error {
@@ -122,9 +274,16 @@ func (appender *RollingFileAppender) flush() error {
 
 	appender.buffer = appender.buffer[:0]
 	appender.currentFileSize += n
+	appender.unsynced = appender.unsynced || n > 0
+
+	if appender.syncMode == SyncOnFlush {
+		if err := appender.sync(); err != nil {
+			return err
+		}
+	}
 
 	if appender.currentFileSize >= appender.fileSize {
-		if err := appender.nextFile(); err != nil {
+		if err := appender.nextFile(appender.rotationTime(appender.now())); err != nil {
 			return err
 		}
 	}
@@ -132,26 +291,127 @@ func (appender *RollingFileAppender) flush() error {
 	return nil
 }
 
-func (appender *RollingFileAppender) nextFile() error {
+// rotate writes the buffer to the current file and switches to the next one.
+func (appender *RollingFileAppender) rotate() error {
+	t := appender.updateRotation()
+	n, err := appender.currentFile.Write(appender.buffer)
+
+	if err != nil {
+		return err
+	}
+
+	appender.buffer = appender.buffer[:0]
+	appender.currentFileSize += n
+	appender.unsynced = appender.unsynced || n > 0
+	return appender.nextFile(t)
+}
+
+func (appender *RollingFileAppender) nextFile(t time.Time) error {
+	path, err := appender.nextPath(t)
+
+	if err != nil {
+		return err
+	}
+
+	// the schema returned the name of the current file, like a TimeNameSchema without sequence number does within a period,
+	// so it's kept instead of reopening it on every flush, as its size stays above the limit
+	if appender.currentFile != nil && appender.currentFile.Name() == path {
+		return nil
+	}
+
 	if appender.currentFile != nil {
+		if err := appender.sync(); err != nil {
+			return err
+		}
+
 		if err := appender.currentFile.Close(); err != nil {
 			return err
 		}
+
+		if appender.compressor != nil && appender.currentFile.Name() != path {
+			appender.compress(appender.currentFile.Name())
+		}
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+
+	if appender.openMode == OpenTruncate {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
 	}
 
-	path := filepath.Join(appender.fileDir, appender.fileName.Name())
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0664)
+	f, err := os.OpenFile(path, flag, 0664)
 
 	if err != nil {
 		return err
 	}
 
+	info, err := f.Stat()
+
+	if err != nil {
+		f.Close()
+		return err
+	}
+
 	appender.currentFile = f
-	appender.currentFileSize = 0
-	return appender.updateFiles(path)
+	appender.currentFileSize = int(info.Size())
+	appender.unsynced = false
+
+	// the interval of RotateEvery starts when the file is opened, regardless of the reason for the rotation
+	if appender.rotationInterval > 0 {
+		appender.updateRotation()
+	}
+
+	if err := appender.updateFiles(path); err != nil {
+		return err
+	}
+
+	appender.updateSymlink()
+	return appender.syncDir()
+}
+
+// nextPath returns the path for the next log file, skipping existing files if required by the OpenMode.
+func (appender *RollingFileAppender) nextPath(t time.Time) (string, error) {
+	path := filepath.Join(appender.fileDir, appender.name(t))
+
+	if appender.openMode != OpenSkip {
+		return path, nil
+	}
+
+	for i := 0; i < maxSkippedNames; i++ {
+		if !appender.fileExists(path) {
+			return path, nil
+		}
+
+		path = filepath.Join(appender.fileDir, appender.name(t))
+	}
+
+	return "", errors.New("filename schema did not return an unused file name")
+}
+
+// fileExists returns whether the log file at given path or its compressed version exists.
+func (appender *RollingFileAppender) fileExists(path string) bool {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return true
+	}
+
+	if appender.compressor != nil {
+		if _, err := os.Stat(path + appender.compressor.Extension()); !os.IsNotExist(err) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (appender *RollingFileAppender) updateFiles(path string) error {
+	// the schema might return the name of an existing file, which becomes the newest one
+	for i, name := range appender.fileNames {
+		if name == path {
+			appender.fileNames = append(appender.fileNames[:i], appender.fileNames[i+1:]...)
+			break
+		}
+	}
+
 	appender.fileNames = append(appender.fileNames, path)
 
 	if len(appender.fileNames) > appender.files {
@@ -160,11 +420,102 @@ func (appender *RollingFileAppender) updateFiles(path string) error {
 		appender.fileNames = appender.fileNames[n:]
 
 		for _, file := range filesToDelete {
-			if err := os.Remove(file); err != nil {
+			if err := appender.removeFile(file, DeleteFileLimit); err != nil {
 				return err
 			}
 		}
 	}
 
+	return appender.applyRetention()
+}
+
+// removeFile removes a log file and its compressed version.
+// Deleted files are passed to the delete handler when the lock is released.
+func (appender *RollingFileAppender) removeFile(path string, reason DeleteReason) error {
+	paths := []string{path}
+
+	if appender.compressor != nil {
+		paths = append(paths, path+appender.compressor.Extension())
+
+		// the compressed file is deleted when the compression has finished
+		if _, ok := appender.compressing[path]; ok {
+			appender.compressing[path] = &deletedFile{path, reason}
+		}
+	}
+
+	for _, p := range paths {
+		if err := os.Remove(p); err == nil {
+			if appender.deleteHandler != nil {
+				appender.deleted = append(appender.deleted, deletedFile{p, reason})
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (appender *RollingFileAppender) name(t time.Time) string {
+	if schema, ok := appender.fileName.(TimeNameSchema); ok {
+		return schema.NameAt(t)
+	}
+
+	return appender.fileName.Name()
+}
+
+// unlock releases the lock and passes deleted files to the delete handler
+// and errors which must not stop logging to the error handler afterwards,
+// so that the handlers can safely write log output.
+func (appender *RollingFileAppender) unlock() {
+	deleted, symlinkErrs := appender.deleted, appender.symlinkErrs
+	appender.deleted, appender.symlinkErrs = nil, nil
+	appender.m.Unlock()
+
+	for _, file := range deleted {
+		appender.deleteHandler(file.path, file.reason)
+	}
+
+	// the symbolic link is optional, so errors are not returned by Write, Flush or Close
+	if appender.errorHandler != nil {
+		for _, err := range symlinkErrs {
+			appender.errorHandler(err)
+		}
+	}
+}
+
+// reportErr passes errors occurring in background tasks to the error handler.
+// If there is none, the error is returned by the next call to Write, Flush or Close.
+// It must be called without holding the lock.
+func (appender *RollingFileAppender) reportErr(err error) {
+	if appender.errorHandler != nil {
+		appender.errorHandler(err)
+		return
+	}
+
+	appender.m.Lock()
+	defer appender.m.Unlock()
+
+	if appender.err == nil {
+		appender.err = err
+	}
+}
+
+func (appender *RollingFileAppender) backgroundErr() error {
+	err := appender.err
+	appender.err = nil
+	return err
+}
+
+// joinErrors returns the error if only one of them is set, or an error containing both messages which unwraps to the first one.
+func joinErrors(err, other error) error {
+	if err == nil {
+		return other
+	}
+
+	if other == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w; %v", err, other)
+}
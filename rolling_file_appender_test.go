@@ -1,6 +1,7 @@
 package logbuch
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -159,3 +160,135 @@ func TestRollingFileAppender_Close(t *testing.T) {
 		t.Fatalf("The log must have been saved, but was: %v", string(content))
 	}
 }
+
+func TestRollingFileAppender_BackgroundErr(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(3, 100, 60, "out", &testNameSchema{})
+
+	if err != nil {
+		t.Fatalf("Appender must be created, but was: %v", err)
+	}
+
+	rfa.reportErr(errors.New("first"))
+
+	if n, err := rfa.Write([]byte("written\n")); n != 8 || err == nil || err.Error() != "first" {
+		t.Fatalf("Background error must be returned without dropping the data, but was: %v %v", n, err)
+	}
+
+	rfa.reportErr(errors.New("second"))
+
+	if _, err := rfa.Write([]byte("buffered\n")); err == nil {
+		t.Fatal("Background error must be returned")
+	}
+
+	rfa.reportErr(errors.New("third"))
+
+	if err := rfa.Close(); err == nil || err.Error() != "third" {
+		t.Fatalf("Background error must be returned on close, but was: %v", err)
+	}
+
+	assertFileContent(t, "out/1_log.txt", "written\nbuffered\n")
+
+	if err := rfa.currentFile.Close(); err == nil {
+		t.Fatal("File must have been closed")
+	}
+}
+
+func TestRollingFileAppender_OpenExisting(t *testing.T) {
+	input := []struct {
+		mode     OpenMode
+		expected map[string]string
+	}{
+		{OpenAppend, map[string]string{"out/1_log.txt": "old\nnew\n", "out/2_log.txt": "old\n"}},
+		{OpenTruncate, map[string]string{"out/1_log.txt": "new\n", "out/2_log.txt": "old\n"}},
+		{OpenSkip, map[string]string{"out/1_log.txt": "old\n", "out/2_log.txt": "old\n", "out/3_log.txt": "new\n"}},
+	}
+
+	for _, in := range input {
+		if err := os.RemoveAll("out"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.MkdirAll("out", 0774); err != nil {
+			t.Fatal(err)
+		}
+
+		// simulate a restart
+		for _, name := range []string{"out/1_log.txt", "out/2_log.txt"} {
+			if err := os.WriteFile(name, []byte("old\n"), 0664); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		rfa, err := NewRollingFileAppender(5, 100, 10, "out", &testNameSchema{}, OpenExisting(in.mode))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if in.mode == OpenAppend && rfa.currentFileSize != 4 {
+			t.Fatalf("Size of existing file must be counted, but was: %v", rfa.currentFileSize)
+		}
+
+		if _, err := rfa.Write([]byte("new\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := rfa.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, expected := range in.expected {
+			content, err := os.ReadFile(name)
+
+			if err != nil || string(content) != expected {
+				t.Fatalf("Expected '%v' in %v for mode %v, but was: %v %v", expected, name, in.mode, err, string(content))
+			}
+		}
+	}
+}
+
+func TestRollingFileAppender_OpenExistingAppendRotate(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile("out/1_log.txt", []byte("123456789\n"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(5, 12, 1, "out", &testNameSchema{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"abc\n", "def\n"} {
+		if _, err := rfa.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile("out/1_log.txt")
+
+	if err != nil || string(content) != "123456789\nabc\n" {
+		t.Fatalf("Existing file must have been rotated when the size limit was reached, but was: %v %v", err, string(content))
+	}
+
+	content, err = os.ReadFile("out/2_log.txt")
+
+	if err != nil || string(content) != "def\n" {
+		t.Fatalf("Next file must contain remaining data, but was: %v %v", err, string(content))
+	}
+}
@@ -0,0 +1,149 @@
+package logbuch
+
+import (
+	"time"
+)
+
+// RotationPeriod is a calendar period used for time based rotation by the RollingFileAppender.
+type RotationPeriod int
+
+const (
+	// RotateNever disables calendar based rotation.
+	RotateNever RotationPeriod = iota
+
+	// RotateHourly rotates at the start of each hour.
+	RotateHourly
+
+	// RotateDaily rotates at midnight.
+	RotateDaily
+
+	// RotateWeekly rotates at midnight from Sunday to Monday.
+	RotateWeekly
+
+	// RotateMonthly rotates at midnight on the first day of each month.
+	RotateMonthly
+)
+
+// RotateEvery makes the RollingFileAppender rotate log files in given interval, starting when the file was opened,
+// which includes rotations because of the size limit.
+// It can be combined with the size limit and RotateOn, in which case the file is rotated by whatever happens first.
+func RotateEvery(interval time.Duration) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.rotationInterval = interval
+	}
+}
+
+// RotateOn makes the RollingFileAppender rotate log files at the start of each calendar period in given time zone.
+// The location defaults to time.Local if nil is passed.
+// The start of the period is passed to the NameSchema in case it implements the TimeNameSchema interface,
+// so that files can be named like app-2006-01-02.log.
+// It can be combined with the size limit and RotateEvery, in which case the file is rotated by whatever happens first.
+// Rotating because of the size limit requires a NameSchema returning a new name within the same period,
+// for example by using the {seq} placeholder of the PatternNameSchema. Otherwise, the current file keeps growing until the next period.
+func RotateOn(period RotationPeriod, location *time.Location) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.rotationPeriod = period
+
+		if location != nil {
+			appender.location = location
+		}
+	}
+}
+
+// start returns the start of the period containing t.
+func (period RotationPeriod) start(t time.Time) time.Time {
+	y, m, d := t.Date()
+
+	switch period {
+	case RotateHourly:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case RotateWeekly:
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case RotateMonthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return t
+	}
+}
+
+// next returns the start of the period following the one starting at start.
+func (period RotationPeriod) next(start time.Time) time.Time {
+	switch period {
+	case RotateHourly:
+		return start.Add(time.Hour)
+	case RotateDaily:
+		return start.AddDate(0, 0, 1)
+	case RotateWeekly:
+		return start.AddDate(0, 0, 7)
+	case RotateMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+func (appender *RollingFileAppender) hasTimeRotation() bool {
+	return appender.rotationInterval > 0 || appender.rotationPeriod != RotateNever
+}
+
+// updateRotation calculates the time of the next rotation and returns the rotation time passed to the NameSchema.
+func (appender *RollingFileAppender) updateRotation() time.Time {
+	now := appender.now().In(appender.location)
+	t := appender.rotationTime(now)
+	appender.nextRotation = time.Time{}
+
+	if appender.rotationPeriod != RotateNever {
+		appender.nextRotation = appender.rotationPeriod.next(t)
+	}
+
+	if appender.rotationInterval > 0 {
+		next := now.Add(appender.rotationInterval)
+
+		if appender.nextRotation.IsZero() || next.Before(appender.nextRotation) {
+			appender.nextRotation = next
+		}
+	}
+
+	return t
+}
+
+// rotationTime returns the time passed to the NameSchema for a rotation at given time.
+func (appender *RollingFileAppender) rotationTime(now time.Time) time.Time {
+	if appender.rotationPeriod != RotateNever {
+		return appender.rotationPeriod.start(now.In(appender.location))
+	}
+
+	return now.In(appender.location)
+}
+
+// rotateOnSchedule rotates the log file when the next rotation is due, even if nothing is written.
+func (appender *RollingFileAppender) rotateOnSchedule() {
+	defer appender.wg.Done()
+
+	for {
+		appender.m.Lock()
+		timer := time.NewTimer(appender.nextRotation.Sub(appender.now()))
+		appender.m.Unlock()
+
+		select {
+		case <-appender.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			var err error
+			appender.m.Lock()
+
+			if !appender.now().Before(appender.nextRotation) {
+				err = appender.rotate()
+			}
+
+			appender.unlock()
+
+			if err != nil {
+				appender.reportErr(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package logbuch
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testTimeNameSchema struct {
+	layout string
+}
+
+func (schema *testTimeNameSchema) Name() string {
+	return schema.NameAt(time.Now())
+}
+
+func (schema *testTimeNameSchema) NameAt(t time.Time) string {
+	return t.Format(schema.layout)
+}
+
+type testClock struct {
+	m   sync.Mutex
+	now time.Time
+}
+
+func (clock *testClock) Now() time.Time {
+	clock.m.Lock()
+	defer clock.m.Unlock()
+	return clock.now
+}
+
+func (clock *testClock) Set(t time.Time) {
+	clock.m.Lock()
+	defer clock.m.Unlock()
+	clock.now = t
+}
+
+func withClock(clock *testClock) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.now = clock.Now
+	}
+}
+
+func TestRotationPeriod(t *testing.T) {
+	location := time.FixedZone("test", 2*60*60)
+	now := time.Date(2026, 10, 18, 13, 14, 15, 16, location) // Sunday
+	input := []RotationPeriod{RotateHourly, RotateDaily, RotateWeekly, RotateMonthly}
+	expectedStart := []time.Time{
+		time.Date(2026, 10, 18, 13, 0, 0, 0, location),
+		time.Date(2026, 10, 18, 0, 0, 0, 0, location),
+		time.Date(2026, 10, 12, 0, 0, 0, 0, location),
+		time.Date(2026, 10, 1, 0, 0, 0, 0, location),
+	}
+	expectedNext := []time.Time{
+		time.Date(2026, 10, 18, 14, 0, 0, 0, location),
+		time.Date(2026, 10, 19, 0, 0, 0, 0, location),
+		time.Date(2026, 10, 19, 0, 0, 0, 0, location),
+		time.Date(2026, 11, 1, 0, 0, 0, 0, location),
+	}
+
+	for i, period := range input {
+		start := period.start(now)
+
+		if !start.Equal(expectedStart[i]) {
+			t.Fatalf("Expected start %v for period %v, but was: %v", expectedStart[i], period, start)
+		}
+
+		if next := period.next(start); !next.Equal(expectedNext[i]) {
+			t.Fatalf("Expected next %v for period %v, but was: %v", expectedNext[i], period, next)
+		}
+	}
+}
+
+func TestRollingFileAppenderRotateOn(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &testClock{now: time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC)}
+	rfa, err := NewRollingFileAppender(5, 1000, 100, "out", &testTimeNameSchema{layout: "app-2006-01-02.log"},
+		RotateOn(RotateDaily, time.UTC),
+		withClock(clock))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rfa.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Set(time.Date(2026, 10, 19, 0, 0, 1, 0, time.UTC))
+
+	if _, err := rfa.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile("out/app-2026-10-18.log")
+
+	if err != nil || string(content) != "first\n" {
+		t.Fatalf("First file must contain buffered data, but was: %v %v", err, string(content))
+	}
+
+	content, err = os.ReadFile("out/app-2026-10-19.log")
+
+	if err != nil || string(content) != "second\n" {
+		t.Fatalf("Second file must contain new data, but was: %v %v", err, string(content))
+	}
+}
+
+func TestRollingFileAppenderRotateEvery(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(10, 1000, 100, "out", &testNameSchema{}, RotateEvery(time.Millisecond*50))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rfa.Write([]byte("buffered\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// rotation must happen without further writes
+	time.Sleep(time.Millisecond * 180)
+
+	if _, err := os.Stat("out/3_log.txt"); err != nil {
+		t.Fatalf("File must have been rotated in background, but was: %v", err)
+	}
+
+	content, err := os.ReadFile("out/1_log.txt")
+
+	if err != nil || string(content) != "buffered\n" {
+		t.Fatalf("Buffered data must have been written on rotation, but was: %v %v", err, string(content))
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRollingFileAppenderRotateOnSizeWithoutSequence(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &testClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	rfa, err := NewRollingFileAppender(5, 10, 1, "out", &testTimeNameSchema{layout: "app-2006-01-02.log"},
+		RotateOn(RotateDaily, time.UTC),
+		withClock(clock))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := rfa.currentFile
+
+	for i := 0; i < 20; i++ {
+		if _, err := rfa.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if rfa.currentFile != file {
+		t.Fatal("File must not be reopened if the schema returns the same name")
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile("out/app-2026-10-18.log")
+
+	if err != nil || len(content) != 120 {
+		t.Fatalf("All data must have been written to the current file, but was: %v %v", err, len(content))
+	}
+}
+
+func TestRollingFileAppenderRotateEveryResetOnSize(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	clock := &testClock{now: start}
+	rfa, err := NewRollingFileAppender(5, 5, 1, "out", &testNameSchema{}, RotateEvery(time.Hour), withClock(clock))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Set(start.Add(time.Minute * 30))
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("1234\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rfa.m.Lock()
+	next := rfa.nextRotation
+	rfa.m.Unlock()
+
+	if !next.Equal(start.Add(time.Minute * 90)) {
+		t.Fatalf("Interval must start when the file was rotated because of its size, but was: %v", next)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,120 @@
+package logbuch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SamplingPolicy limits how often entries with the same message template are logged.
+// Within each interval, the first entries are logged, then every Thereafter-th entry.
+// At the end of the interval, a summary line with the number of suppressed entries is logged.
+type SamplingPolicy struct {
+	// Interval is the time window entries are counted in.
+	Interval time.Duration
+
+	// First is the number of entries logged per interval before sampling starts.
+	First int
+
+	// Thereafter logs every Thereafter-th entry after the first ones. Set it to 0 to suppress all of them.
+	Thereafter int
+}
+
+type samplingKey struct {
+	level    int
+	template string
+}
+
+type samplingCounter struct {
+	count      int
+	suppressed int
+}
+
+type sampler struct {
+	policies [LevelError + 1]*SamplingPolicy
+	counters map[samplingKey]*samplingCounter
+	m        sync.Mutex
+}
+
+// SetSampling sets the SamplingPolicy for given level. Pass nil to disable sampling for the level, which is the default.
+// Setting the policy starts new intervals for the level. Summaries of the current ones are still logged.
+// Entries logged by Fatal are never sampled.
+// Entries are keyed by their unformatted message, so that "Request to %s failed" is sampled regardless of the parameters.
+// The summary line is logged at the same level, like "suppressed 39000 similar messages: Request to %s failed".
+//
+//  logbuch.SetSampling(logbuch.LevelWarning, &logbuch.SamplingPolicy{Interval: time.Second, First: 10, Thereafter: 100})
+func (log *Logger) SetSampling(level int, policy *SamplingPolicy) {
+	log = log.root()
+	log.sampler.m.Lock()
+	defer log.sampler.m.Unlock()
+
+	if policy != nil && (policy.Interval <= 0 || policy.First < 0 || policy.Thereafter < 0) {
+		policy = nil
+	}
+
+	level = getValidLevel(level)
+	log.sampler.policies[level] = policy
+
+	for key := range log.sampler.counters {
+		if key.level == level {
+			delete(log.sampler.counters, key)
+		}
+	}
+}
+
+// sample returns whether an entry of given level and message template is logged.
+// The sampler has its own lock, as the Logger might be locked while writing in asynchronous mode.
+func (log *Logger) sample(level int, template string) bool {
+	log = log.root()
+	s := &log.sampler
+	s.m.Lock()
+	defer s.m.Unlock()
+	policy := s.policies[level]
+
+	if policy == nil {
+		return true
+	}
+
+	if s.counters == nil {
+		s.counters = make(map[samplingKey]*samplingCounter)
+	}
+
+	key := samplingKey{level, template}
+	counter, ok := s.counters[key]
+
+	if !ok {
+		counter = new(samplingCounter)
+		s.counters[key] = counter
+		time.AfterFunc(policy.Interval, func() {
+			log.endSamplingInterval(key, counter)
+		})
+	}
+
+	counter.count++
+
+	if counter.count <= policy.First || (policy.Thereafter > 0 && (counter.count-policy.First)%policy.Thereafter == 0) {
+		return true
+	}
+
+	counter.suppressed++
+	return false
+}
+
+// endSamplingInterval removes the counter for given key and logs the summary line if entries have been suppressed.
+// The counter might have been replaced already, in case the policy has been changed using SetSampling.
+func (log *Logger) endSamplingInterval(key samplingKey, counter *samplingCounter) {
+	s := &log.sampler
+	s.m.Lock()
+
+	if s.counters[key] == counter {
+		delete(s.counters, key)
+	}
+
+	suppressed := counter.suppressed
+	s.m.Unlock()
+
+	if suppressed > 0 {
+		msg := fmt.Sprintf("suppressed %d similar messages: %s", suppressed, key.template)
+		log.log(-1, key.level, nil, msg, nil)
+	}
+}
@@ -0,0 +1,144 @@
+package logbuch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerSampling(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.SetSampling(LevelWarning, &SamplingPolicy{Interval: time.Hour, First: 2, Thereafter: 3})
+
+	for i := 0; i < 10; i++ {
+		l.Warn("warning %d", i)
+	}
+
+	l.Warn("other")
+	l.Info("info")
+	l.Info("info")
+	expected := "[WARN ] warning 0\n[WARN ] warning 1\n[WARN ] warning 4\n[WARN ] warning 7\n[WARN ] other\n[INFO ] info\n[INFO ] info\n"
+
+	if out.String() != expected {
+		t.Fatalf("Entries must be sampled by level and template, but was: %q", out.String())
+	}
+
+	l.SetSampling(LevelWarning, nil)
+	out.Reset()
+	l.Warn("warning %d", 10)
+
+	if out.String() != "[WARN ] warning 10\n" {
+		t.Fatalf("Sampling must be disabled, but was: %q", out.String())
+	}
+}
+
+func TestLoggerSamplingSuppressAll(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.With(Fields{"key": "value"}).SetSampling(LevelError, &SamplingPolicy{Interval: time.Hour, First: 1})
+
+	for i := 0; i < 5; i++ {
+		l.Error("error")
+	}
+
+	if out.String() != "[ERROR] error\n" {
+		t.Fatalf("All entries after the first must be suppressed, but was: %q", out.String())
+	}
+}
+
+func TestLoggerSamplingSummary(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.SetSampling(LevelWarning, &SamplingPolicy{Interval: time.Millisecond * 20, First: 1})
+
+	for i := 0; i < 5; i++ {
+		l.Warn("Request to %s failed", "example.com")
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	l.Warn("Request to %s failed", "example.com")
+	l.m.Lock()
+	result := out.String()
+	l.m.Unlock()
+	expected := "[WARN ] Request to example.com failed\n" +
+		"[WARN ] suppressed 4 similar messages: Request to %s failed\n" +
+		"[WARN ] Request to example.com failed\n"
+
+	if result != expected {
+		t.Fatalf("Summary must be logged at the end of the interval, but was: %q", result)
+	}
+}
+
+func TestLoggerSamplingReset(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	policy := &SamplingPolicy{Interval: time.Hour, First: 1}
+	l.SetSampling(LevelInfo, policy)
+	l.Info("info")
+	l.Info("info")
+	l.SetSampling(LevelInfo, nil)
+	l.SetSampling(LevelInfo, policy)
+	l.Info("info")
+	l.Info("info")
+
+	if out.String() != "[INFO ] info\n[INFO ] info\n" {
+		t.Fatalf("Setting the policy must reset the counters, but was: %q", out.String())
+	}
+}
+
+func TestLoggerSamplingFatal(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.SetSampling(LevelError, &SamplingPolicy{Interval: time.Hour, First: 1})
+	l.Error("fatal")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Fatal must panic")
+		}
+
+		if out.String() != "[ERROR] fatal\n[ERROR] fatal\n" {
+			t.Fatalf("Fatal must not be sampled, but was: %q", out.String())
+		}
+	}()
+
+	l.Fatal("fatal")
+}
+
+func TestLoggerSamplingInvalidPolicy(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.SetSampling(LevelInfo, &SamplingPolicy{First: 1})
+
+	for i := 0; i < 3; i++ {
+		l.Info("info")
+	}
+
+	if strings.Count(out.String(), "info") != 3 {
+		t.Fatalf("Invalid policy must disable sampling, but was: %q", out.String())
+	}
+}
+
+func TestFuncsSampling(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out, &out)
+	SetFormatter(NewStandardFormatter(""))
+	SetSampling(LevelDebug, &SamplingPolicy{Interval: time.Hour, First: 1})
+	defer SetSampling(LevelDebug, nil)
+	SetLevel(LevelDebug)
+	defer SetLevel(LevelInfo)
+	Debug("debug")
+	Debug("debug")
+
+	if out.String() != "[DEBUG] debug\n" {
+		t.Fatalf("Default logger must be sampled, but was: %q", out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package logbuch
+
+import (
+	"io"
+	"sync"
+)
+
+// Sink writes log entries to an io.Writer using its own Formatter.
+// A Logger writes each entry to its default sink, made up of the outputs and formatter set by NewLogger, SetOut and SetFormatter,
+// and to all sinks added using AddSink, which allows to write human-readable text to the console and JSON to a file at the same time.
+// A Sink can be shared between Loggers.
+type Sink struct {
+	out       io.Writer
+	formatter Formatter
+	level     int
+	buffer    []byte
+	m         sync.Mutex
+}
+
+// NewSink creates a new Sink writing entries of given level and above to the io.Writer using the Formatter.
+// If the formatter is nil, the StandardFormatter is used.
+// The log level of the Logger applies before the level of the sink.
+//
+//  logbuch.AddSink(logbuch.NewSink(file, logbuch.NewJSONFormatter(time.RFC3339), logbuch.LevelInfo))
+func NewSink(out io.Writer, formatter Formatter, level int) *Sink {
+	if formatter == nil {
+		formatter = NewStandardFormatter(StandardTimeFormat)
+	}
+
+	return &Sink{out: out, formatter: formatter, level: getValidLevel(level)}
+}
+
+// GetLevel returns the minimum level of entries written by the sink.
+func (sink *Sink) GetLevel() int {
+	return sink.level
+}
+
+// GetFormatter returns the formatter of the sink.
+func (sink *Sink) GetFormatter() Formatter {
+	return sink.formatter
+}
+
+// GetOut returns the io.Writer of the sink.
+func (sink *Sink) GetOut() io.Writer {
+	return sink.out
+}
+
+func (sink *Sink) write(entry *Entry) error {
+	if entry.Level < sink.level {
+		return nil
+	}
+
+	sink.m.Lock()
+	defer sink.m.Unlock()
+	sink.buffer = sink.buffer[:0]
+	formatEntry(sink.formatter, &sink.buffer, entry)
+
+	if len(sink.buffer) == 0 {
+		return nil
+	}
+
+	return writeLevel(sink.out, entry.Level, sink.buffer)
+}
+
+// AddSink adds sinks the Logger writes to in addition to its default sink.
+func (log *Logger) AddSink(sinks ...*Sink) {
+	log = log.root()
+	log.m.Lock()
+	defer log.m.Unlock()
+	log.sinks = append(log.sinks, sinks...)
+}
+
+// RemoveSink removes a sink added using AddSink.
+func (log *Logger) RemoveSink(sink *Sink) {
+	log = log.root()
+	log.m.Lock()
+	defer log.m.Unlock()
+
+	for i, s := range log.sinks {
+		if s == sink {
+			log.sinks = append(log.sinks[:i], log.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetSinks returns the sinks added using AddSink.
+func (log *Logger) GetSinks() []*Sink {
+	log = log.root()
+	log.m.Lock()
+	defer log.m.Unlock()
+	return append([]*Sink(nil), log.sinks...)
+}
@@ -0,0 +1,79 @@
+package logbuch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (writer failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestLoggerSinks(t *testing.T) {
+	var stdout, stderr, jsonOut, errOut bytes.Buffer
+	l := NewLogger(&stdout, &stderr)
+	l.SetFormatter(NewStandardFormatter(""))
+	jsonSink := NewSink(&jsonOut, NewJSONFormatter(""), LevelDebug)
+	errSink := NewSink(&errOut, nil, LevelWarning)
+	l.AddSink(jsonSink, errSink)
+	l.With(Fields{"key": "value"}).Info("info")
+	l.Error("error")
+
+	if stdout.String() != "[INFO ] info key=value\n" || stderr.String() != "[ERROR] error\n" {
+		t.Fatalf("Default sink must have been written, but was: %q %q", stdout.String(), stderr.String())
+	}
+
+	if jsonOut.String() != `{"level":"info","msg":"info","key":"value"}`+"\n"+`{"level":"error","msg":"error"}`+"\n" {
+		t.Fatalf("Unexpected JSON output: %q", jsonOut.String())
+	}
+
+	if errOut.Len() == 0 || bytes.Contains(errOut.Bytes(), []byte("info")) {
+		t.Fatalf("Sink must only write entries of its level and above, but was: %q", errOut.String())
+	}
+
+	if sinks := l.GetSinks(); len(sinks) != 2 || sinks[0] != jsonSink || sinks[1] != errSink {
+		t.Fatalf("Unexpected sinks: %v", sinks)
+	}
+
+	l.RemoveSink(jsonSink)
+	jsonOut.Reset()
+	l.Info("info")
+
+	if jsonOut.Len() != 0 {
+		t.Fatalf("Removed sink must not be written, but was: %q", jsonOut.String())
+	}
+}
+
+func TestLoggerSinksOnly(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(nil, nil)
+	l.AddSink(NewSink(&out, NewStandardFormatter(""), LevelDebug))
+	l.Info("info")
+
+	if out.String() != "[INFO ] info\n" {
+		t.Fatalf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestLoggerSinkError(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.AddSink(NewSink(failingWriter{}, nil, LevelDebug))
+	l.PanicOnErr = true
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Logger must panic on error")
+		}
+
+		if out.String() != "[INFO ] info\n" {
+			t.Fatalf("Default sink must have been written, but was: %q", out.String())
+		}
+	}()
+
+	l.Info("info")
+}
@@ -0,0 +1,240 @@
+//go:build go1.21
+// +build go1.21
+
+package logbuch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// SlogLevel maps a logbuch level to a slog.Level.
+//
+//  LevelDebug   -> slog.LevelDebug (-4)
+//  LevelInfo    -> slog.LevelInfo (0)
+//  LevelWarning -> slog.LevelWarn (4)
+//  LevelError   -> slog.LevelError (8)
+//
+// Invalid levels are mapped to slog.LevelDebug.
+func SlogLevel(level int) slog.Level {
+	switch getValidLevel(level) {
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// LevelFromSlog maps a slog.Level to a logbuch level.
+// As slog levels are integers, each logbuch level covers a range of slog levels:
+//
+//  level <  slog.LevelInfo  -> LevelDebug
+//  level <  slog.LevelWarn  -> LevelInfo
+//  level <  slog.LevelError -> LevelWarning
+//  level >= slog.LevelError -> LevelError
+func LevelFromSlog(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarning
+	default:
+		return LevelError
+	}
+}
+
+// SlogHandler is a slog.Handler writing records to a Logger.
+// It respects the level, outputs and formatter of the Logger.
+// Attributes are passed to the formatter as fields, groups are added as prefix to the keys separated by a dot.
+// Fields bound to the Logger and extracted from the context are added as well.
+//
+// Example:
+//  slog.SetDefault(slog.New(logbuch.NewSlogHandler(logger)))
+type SlogHandler struct {
+	logger *Logger
+	fields Fields
+	group  string
+}
+
+// NewSlogHandler creates a new SlogHandler for given Logger.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// Enabled returns whether the Logger writes records of given level.
+func (handler *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return LevelFromSlog(level) >= handler.logger.GetLevel()
+}
+
+// Handle writes the record to the Logger.
+func (handler *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(Fields, len(handler.logger.fields)+len(handler.fields)+record.NumAttrs())
+
+	for k, v := range handler.logger.fields {
+		fields[k] = v
+	}
+
+	for k, v := range handler.fields {
+		fields[k] = v
+	}
+
+	level := LevelFromSlog(record.Level)
+	stackTrace := handler.logger.stackTraceEnabled(level)
+	var errs []interface{}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, handler.group, attr)
+
+		if stackTrace {
+			if err, ok := attr.Value.Resolve().Any().(error); ok {
+				errs = append(errs, err)
+			}
+		}
+
+		return true
+	})
+
+	t := record.Time
+
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	entry := &Entry{Level: level,
+		Time:    t,
+		Message: record.Message,
+		Fields:  contextFields(ctx, fields),
+		Stack:   errorStack(errs)}
+
+	if record.PC != 0 && handler.logger.GetReportCaller() {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Caller = &Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+
+	handler.logger.logEntry(entry)
+	return nil
+}
+
+// WithAttrs returns a new SlogHandler adding given attributes to each record.
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return handler
+	}
+
+	fields := make(Fields, len(handler.fields)+len(attrs))
+
+	for k, v := range handler.fields {
+		fields[k] = v
+	}
+
+	for _, attr := range attrs {
+		addSlogAttr(fields, handler.group, attr)
+	}
+
+	return &SlogHandler{logger: handler.logger, fields: fields, group: handler.group}
+}
+
+// WithGroup returns a new SlogHandler adding given group name as prefix to all following attributes.
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	return &SlogHandler{logger: handler.logger, fields: handler.fields, group: handler.group + name + "."}
+}
+
+func addSlogAttr(fields Fields, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, a := range attr.Value.Group() {
+			addSlogAttr(fields, prefix, a)
+		}
+
+		return
+	}
+
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
+
+// SlogFormatter forwards log entries to a slog.Handler instead of formatting them.
+// It doesn't write anything into the buffer, so the outputs of the Logger won't be used.
+// Parameters of type Fields and OrderedFields, as well as fields bound to the Logger, are passed on as attributes.
+// Other parameters are used to format the message like the StandardFormatter does.
+// Errors returned by the slog.Handler are dropped.
+type SlogFormatter struct {
+	handler slog.Handler
+}
+
+// NewSlogFormatter creates a new SlogFormatter for given slog.Handler.
+func NewSlogFormatter(handler slog.Handler) *SlogFormatter {
+	return &SlogFormatter{handler: handler}
+}
+
+// Fmt forwards the message to the slog.Handler.
+func (formatter *SlogFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	formatter.FmtEntry(buffer, &Entry{Level: level, Time: t, Message: msg, Params: params})
+}
+
+// FmtEntry forwards the entry to the slog.Handler.
+func (formatter *SlogFormatter) FmtEntry(buffer *[]byte, entry *Entry) {
+	ctx := context.Background()
+	level := SlogLevel(entry.Level)
+
+	if !formatter.handler.Enabled(ctx, level) {
+		return
+	}
+
+	var ordered OrderedFields
+
+	if len(entry.Params) == 1 {
+		ordered, _ = entry.Params[0].(OrderedFields)
+	}
+
+	fields, params := mergeFields(entry.Fields, entry.Params)
+	msg := entry.Message
+
+	if ordered != nil {
+		params = nil
+	} else if len(params) > 0 {
+		msg = fmt.Sprintf(msg, params...)
+	}
+
+	record := slog.NewRecord(entry.Time, level, msg, 0)
+
+	for _, k := range sortedKeys(fields) {
+		record.AddAttrs(slog.Any(k, fields[k]))
+	}
+
+	for _, field := range ordered {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+
+	_ = formatter.handler.Handle(ctx, record)
+}
+
+// Pnc formats the given message and panics.
+func (formatter *SlogFormatter) Pnc(msg string, params []interface{}) {
+	if len(params) == 0 {
+		panic(msg)
+	} else {
+		panic(fmt.Sprintf(msg, params...))
+	}
+}
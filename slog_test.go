@@ -0,0 +1,144 @@
+//go:build go1.21
+// +build go1.21
+
+package logbuch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSlogLevel(t *testing.T) {
+	input := []int{LevelDebug, LevelInfo, LevelWarning, LevelError, -1, 42}
+	expected := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, slog.LevelDebug, slog.LevelDebug}
+
+	for i, in := range input {
+		if level := SlogLevel(in); level != expected[i] {
+			t.Fatalf("Expected level %v for %v, but was: %v", expected[i], in, level)
+		}
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	input := []slog.Level{slog.LevelDebug - 4, slog.LevelDebug, slog.LevelInfo - 1, slog.LevelInfo, slog.LevelInfo + 2, slog.LevelWarn, slog.LevelError - 1, slog.LevelError, slog.LevelError + 4}
+	expected := []int{LevelDebug, LevelDebug, LevelDebug, LevelInfo, LevelInfo, LevelWarning, LevelWarning, LevelError, LevelError}
+
+	for i, in := range input {
+		if level := LevelFromSlog(in); level != expected[i] {
+			t.Fatalf("Expected level %v for %v, but was: %v", expected[i], in, level)
+		}
+	}
+
+	for level := LevelDebug; level <= LevelError; level++ {
+		if LevelFromSlog(SlogLevel(level)) != level {
+			t.Fatalf("Level %v must be mapped back to itself", level)
+		}
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := NewLogger(&stdout, &stderr)
+	logger.SetFormatter(NewLogfmtFormatter(""))
+	logger.SetLevel(LevelInfo)
+	l := slog.New(NewSlogHandler(logger.With(Fields{"bound": 1})))
+	l.Debug("Must not be logged")
+	l.Info("Info", "key", "value", slog.Group("group", "a", 1, slog.Group("nested", "b", 2)))
+	l.WithGroup("req").With("id", 42).Warn("Warning", "path", "/")
+	l.Error("Error", "err", "failed")
+
+	if stdout.String() != "level=info msg=Info bound=1 group.a=1 group.nested.b=2 key=value\n"+
+		"level=warn msg=Warning bound=1 req.id=42 req.path=/\n" {
+		t.Fatalf("Unexpected log: %v", stdout.String())
+	}
+
+	if stderr.String() != "level=error msg=Error bound=1 err=failed\n" {
+		t.Fatalf("Unexpected log: %v", stderr.String())
+	}
+
+	if !l.Enabled(context.Background(), slog.LevelError) || l.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("Handler must respect the log level")
+	}
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, &out)
+	logger.SetFormatter(NewLogfmtFormatter(""))
+	logger.SetReportCaller(true)
+	l := slog.New(NewSlogHandler(logger))
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("Info")
+
+	if out.String() != fmt.Sprintf("level=info caller=slog_test.go:%d msg=Info\n", line+1) {
+		t.Fatalf("Unexpected log: %v", out.String())
+	}
+}
+
+func TestSlogHandlerStackTrace(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, &out)
+	logger.SetFormatter(NewFieldFormatter("", "\t"))
+	logger.EnableStackTrace(LevelError)
+	l := slog.New(NewSlogHandler(logger))
+	l.Warn("Warning", "err", newTestStackError())
+
+	if strings.Contains(out.String(), "newTestStackError") {
+		t.Fatalf("Stack trace must only be added for configured level, but was: %q", out.String())
+	}
+
+	out.Reset()
+	l.Error("Error", "count", 1, "err", newTestStackError())
+
+	if !strings.Contains(out.String(), "\ngithub.com/emvi/logbuch.newTestStackError()\n\t") {
+		t.Fatalf("Stack trace of the error must be added, but was: %q", out.String())
+	}
+
+	out.Reset()
+	l.Error("Error", "err", errors.New("no stack"))
+
+	if strings.Contains(out.String(), "\n\t") {
+		t.Fatalf("Stack trace must only be added for errors carrying one, but was: %q", out.String())
+	}
+}
+
+func TestSlogFormatter(t *testing.T) {
+	var out bytes.Buffer
+	handler := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return a
+	}})
+	logger := NewLogger(nil, nil)
+	logger.SetFormatter(NewSlogFormatter(handler))
+	logger.Debug("Dropped by handler")
+	logger.Info("Hello %s!", "World")
+	logger.With(Fields{"bound": true}).Warn("Warning", Fields{"key": "value"})
+	logger.Error("Error", OrderedFields{{"z", 1}, {"a", 2}})
+	expected := "level=INFO msg=\"Hello World!\"\n" +
+		"level=WARN msg=Warning bound=true key=value\n" +
+		"level=ERROR msg=Error z=1 a=2\n"
+
+	if out.String() != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, out.String())
+	}
+}
+
+func TestSlogFormatterPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "message formatted") {
+			t.Fatalf("Formatter must panic with formatted message, but was: %v", r)
+		}
+	}()
+
+	formatter := NewSlogFormatter(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	formatter.Pnc("message %s", []interface{}{"formatted"})
+}
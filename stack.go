@@ -0,0 +1,132 @@
+package logbuch
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	maxStackDepth = 64
+)
+
+// StackTracer is implemented by errors carrying the stack trace of the location they were created at.
+// The stack trace is a list of program counters as returned by runtime.Callers.
+// Errors created by github.com/pkg/errors are supported as well.
+type StackTracer interface {
+	StackTrace() []uintptr
+}
+
+// EnableStackTrace adds a stack trace to entries of given level and above.
+// If an error carrying a stack trace is passed as a parameter, the stack trace of the innermost wrapped error is used instead
+// (see StackTracer). The StandardFormatter and FieldFormatter print the stack trace below the message,
+// the JSON and logfmt formatters add it using the stack key.
+// Entries written through the Writer or StdLogger don't get a stack trace, entries of the SlogHandler only get the stack traces of error attributes.
+func (log *Logger) EnableStackTrace(level int) {
+	atomic.StoreInt32(&log.root().stackTrace, int32(getValidLevel(level)+1))
+}
+
+// DisableStackTrace disables adding stack traces to entries, which is the default.
+func (log *Logger) DisableStackTrace() {
+	atomic.StoreInt32(&log.root().stackTrace, 0)
+}
+
+// stackTraceEnabled returns whether a stack trace is added to entries of given level.
+func (log *Logger) stackTraceEnabled(level int) bool {
+	stackTrace := atomic.LoadInt32(&log.root().stackTrace)
+	return stackTrace > 0 && int32(level+1) >= stackTrace
+}
+
+// stackAt returns the stack trace skipping given number of stack frames above the function calling stackAt.
+func stackAt(skip int) string {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return formatStack(pcs[:n])
+}
+
+// errorStack returns the stack trace of the innermost error carrying one in given parameters.
+func errorStack(params []interface{}) string {
+	for _, param := range params {
+		err, ok := param.(error)
+
+		if !ok {
+			continue
+		}
+
+		var stack []uintptr
+
+		// typed nil errors would panic when calling their methods
+		for ; err != nil && !isNilPointer(err); err = errors.Unwrap(err) {
+			if pcs := stackTrace(err); len(pcs) > 0 {
+				stack = pcs
+			}
+		}
+
+		if len(stack) > 0 {
+			return formatStack(stack)
+		}
+	}
+
+	return ""
+}
+
+// stackTrace returns the stack trace of given error. Besides the StackTracer interface,
+// it supports StackTrace methods returning a named slice of program counters, like github.com/pkg/errors does.
+func stackTrace(err error) []uintptr {
+	if tracer, ok := err.(StackTracer); ok {
+		return tracer.StackTrace()
+	}
+
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	out := method.Type().Out(0)
+
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+
+	return pcs
+}
+
+// formatStack formats the stack trace like the runtime does, with the function on one line followed by the location.
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+
+		if frame.Function != "" {
+			sb.WriteString(frame.Function)
+			sb.WriteString("()\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteByte('\n')
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+package logbuch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type testStackError struct {
+	pcs []uintptr
+}
+
+func newTestStackError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	return &testStackError{pcs[:n]}
+}
+
+func (err *testStackError) Error() string {
+	return "stack error"
+}
+
+func (err *testStackError) StackTrace() []uintptr {
+	return err.pcs
+}
+
+// testFrames mimics the stack trace of github.com/pkg/errors.
+type testFrame uintptr
+type testFrames []testFrame
+
+type testNamedStackError struct {
+	frames testFrames
+}
+
+func newTestNamedStackError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	frames := make(testFrames, n)
+
+	for i := range frames {
+		frames[i] = testFrame(pcs[i])
+	}
+
+	return &testNamedStackError{frames}
+}
+
+func (err *testNamedStackError) Error() string {
+	return "named stack error"
+}
+
+func (err *testNamedStackError) StackTrace() testFrames {
+	return err.frames
+}
+
+func TestLoggerStackTrace(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.Error("disabled")
+
+	if out.String() != "[ERROR] disabled\n" {
+		t.Fatalf("Stack trace must be disabled by default, but was: %q", out.String())
+	}
+
+	l.EnableStackTrace(LevelWarning)
+	out.Reset()
+	l.Info("info")
+
+	if out.String() != "[INFO ] info\n" {
+		t.Fatalf("Stack trace must only be added for configured level and above, but was: %q", out.String())
+	}
+
+	out.Reset()
+	l.Warn("warn")
+
+	if !strings.HasPrefix(out.String(), "[WARN ] warn\ngithub.com/emvi/logbuch.TestLoggerStackTrace()\n\t") ||
+		!strings.Contains(out.String(), "stack_test.go:") {
+		t.Fatalf("Stack trace must start at the caller, but was: %q", out.String())
+	}
+
+	l.DisableStackTrace()
+	out.Reset()
+	l.Error("disabled")
+
+	if out.String() != "[ERROR] disabled\n" {
+		t.Fatalf("Stack trace must be disabled, but was: %q", out.String())
+	}
+}
+
+func TestLoggerStackTraceConcurrent(t *testing.T) {
+	l := NewLogger(io.Discard, io.Discard)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			l.Warn("warning")
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			l.EnableStackTrace(LevelError)
+		} else {
+			l.DisableStackTrace()
+		}
+	}
+
+	<-done
+}
+
+func TestFuncsStackTrace(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out, &out)
+	EnableStackTrace(LevelError)
+	defer DisableStackTrace()
+	Error("error")
+
+	if !strings.Contains(out.String(), "error\ngithub.com/emvi/logbuch.TestFuncsStackTrace()\n\t") {
+		t.Fatalf("Stack trace must start at the caller, but was: %q", out.String())
+	}
+}
+
+func TestLoggerErrorStackTrace(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.SetFormatter(NewFieldFormatter("", "\t"))
+	l.EnableStackTrace(LevelError)
+	l.Error("failed", fmt.Errorf("wrapped: %w", newTestStackError()))
+
+	if !strings.Contains(out.String(), "\ngithub.com/emvi/logbuch.newTestStackError()\n\t") {
+		t.Fatalf("Stack trace of the error must be used, but was: %q", out.String())
+	}
+
+	out.Reset()
+	l.Error("failed", newTestNamedStackError())
+
+	if !strings.Contains(out.String(), "\ngithub.com/emvi/logbuch.newTestNamedStackError()\n\t") {
+		t.Fatalf("Stack trace of the error must be used, but was: %q", out.String())
+	}
+
+	out.Reset()
+	l.Error("failed", errors.New("no stack"))
+
+	if !strings.Contains(out.String(), "\ngithub.com/emvi/logbuch.TestLoggerErrorStackTrace()\n\t") {
+		t.Fatalf("Stack trace must be captured for errors without one, but was: %q", out.String())
+	}
+	out.Reset()
+	l.Error("failed", (*testStackError)(nil))
+
+	if !strings.Contains(out.String(), "\ngithub.com/emvi/logbuch.TestLoggerErrorStackTrace()\n\t") {
+		t.Fatalf("Stack trace must be captured for typed nil errors, but was: %q", out.String())
+	}
+}
+
+func TestStackTraceStructuredFormatters(t *testing.T) {
+	entry := &Entry{Level: LevelError, Message: "msg", Stack: "main.main()\n\t/src/main.go:42\n"}
+	var buffer []byte
+	NewJSONFormatter("").FmtEntry(&buffer, entry)
+
+	if string(buffer) != `{"level":"error","msg":"msg","stack":"main.main()\n\t/src/main.go:42\n"}`+"\n" {
+		t.Fatalf("Unexpected JSON: %q", string(buffer))
+	}
+
+	buffer = buffer[:0]
+	NewLogfmtFormatter("").FmtEntry(&buffer, entry)
+
+	if string(buffer) != `level=error msg=msg stack="main.main()\n\t/src/main.go:42\n"`+"\n" {
+		t.Fatalf("Unexpected logfmt: %q", string(buffer))
+	}
+}
@@ -1,6 +1,7 @@
 package logbuch
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 )
@@ -11,7 +12,7 @@ const (
 )
 
 // StandardFormatter is the default formatter.
-// It prints log messages starting with the timestamp, followed by the log level and the formatted message.
+// It prints log messages starting with the timestamp, followed by the log level, the caller if enabled and the formatted message.
 type StandardFormatter struct {
 	timeFormat  string
 	disableTime bool
@@ -25,11 +26,17 @@ func NewStandardFormatter(timeFormat string) *StandardFormatter {
 
 // Fmt formats the message as described for the StandardFormatter.
 func (formatter *StandardFormatter) Fmt(buffer *[]byte, level int, t time.Time, msg string, params []interface{}) {
+	formatter.FmtEntry(buffer, &Entry{Level: level, Time: t, Message: msg, Params: params})
+}
+
+// FmtEntry formats the entry as described for the StandardFormatter.
+// Fields bound to the logger are appended to the message as key value pairs. The stack trace, if any, is printed below.
+func (formatter *StandardFormatter) FmtEntry(buffer *[]byte, entry *Entry) {
 	if !formatter.disableTime {
-		*buffer = append(*buffer, t.Format(formatter.timeFormat)+" "...)
+		*buffer = append(*buffer, entry.Time.Format(formatter.timeFormat)+" "...)
 	}
 
-	switch level {
+	switch entry.Level {
 	case LevelDebug:
 		*buffer = append(*buffer, "[DEBUG] "...)
 	case LevelInfo:
@@ -40,15 +47,30 @@ func (formatter *StandardFormatter) Fmt(buffer *[]byte, level int, t time.Time,
 		*buffer = append(*buffer, "[ERROR] "...)
 	}
 
-	if len(params) == 0 {
-		*buffer = append(*buffer, msg...)
+	if entry.Caller != nil {
+		*buffer = append(*buffer, entry.Caller.String()+" "...)
+	}
+
+	if len(entry.Params) == 0 {
+		*buffer = append(*buffer, entry.Message...)
 	} else {
-		*buffer = append(*buffer, fmt.Sprintf(msg, params...)...)
+		*buffer = append(*buffer, fmt.Sprintf(entry.Message, entry.Params...)...)
+	}
+
+	if len(entry.Fields) > 0 {
+		*buffer = bytes.TrimRight(*buffer, "\n")
+
+		for _, k := range sortedKeys(entry.Fields) {
+			*buffer = append(*buffer, fmt.Sprintf(" %s=%v", k, entry.Fields[k])...)
+		}
 	}
 
 	if len(*buffer) == 0 || (*buffer)[len(*buffer)-1] != '\n' {
 		*buffer = append(*buffer, '\n')
 	}
+
+	// the stack trace is printed as a block below the message
+	*buffer = append(*buffer, entry.Stack...)
 }
 
 // Pnc formats the given message and panics.
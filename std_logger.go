@@ -0,0 +1,86 @@
+package logbuch
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"regexp"
+	"sync"
+)
+
+const (
+	stdLogPrefix = `(?:\S{1,32} ?)??`
+	stdLogDate   = `\d{4}/\d{2}/\d{2} `
+	stdLogTime   = `\d{2}:\d{2}:\d{2}(?:\.\d{6})? `
+	stdLogFile   = `\S+\.go:\d+: `
+)
+
+// stdLogHeader matches the header written by the standard library logger: the date and time (log.Ldate, log.Ltime and log.Lmicroseconds)
+// and file (log.Lshortfile and log.Llongfile), optionally preceded by a prefix without spaces, like "http: ".
+var stdLogHeader = regexp.MustCompile("^" + stdLogPrefix + "(?:" +
+	stdLogDate + "(?:" + stdLogTime + ")?(?:" + stdLogFile + ")?|" +
+	stdLogTime + "(?:" + stdLogFile + ")?|" +
+	stdLogFile + ")")
+
+type levelWriter struct {
+	m       sync.Mutex
+	logger  *Logger
+	level   int
+	pending []byte
+}
+
+// Write logs each line in p as an entry of the configured level.
+// Incomplete lines are kept until the line is completed by the next call.
+func (writer *levelWriter) Write(p []byte) (int, error) {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	writer.pending = append(writer.pending, p...)
+
+	for {
+		i := bytes.IndexByte(writer.pending, '\n')
+
+		if i == -1 {
+			break
+		}
+
+		writer.writeLine(writer.pending[:i])
+		writer.pending = writer.pending[i+1:]
+	}
+
+	if len(writer.pending) == 0 {
+		writer.pending = nil
+	}
+
+	return len(p), nil
+}
+
+func (writer *levelWriter) writeLine(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	line = line[len(stdLogHeader.Find(line)):]
+
+	if len(line) == 0 || writer.logger.GetLevel() > writer.level {
+		return
+	}
+
+	// the depth of the standard library logger is unknown, so the caller is not captured
+	writer.logger.log(-1, writer.level, writer.logger.fields, string(line), nil)
+}
+
+// Writer returns an io.Writer logging each line written to it as an entry of given level.
+// The header written by the standard library logger in front of the lines is removed: the prefix, date, time and file.
+// Prefixes are only removed together with a header. Prefixes containing spaces or written after the header (log.Lmsgprefix) are kept,
+// as they cannot be told apart from the message.
+// Incomplete lines are buffered until the next newline is written.
+func (log *Logger) Writer(level int) io.Writer {
+	return &levelWriter{logger: log, level: getValidLevel(level)}
+}
+
+// StdLogger returns a standard library logger writing each line as an entry of given level.
+// This can be used for third-party libraries requiring a *log.Logger, like http.Server.ErrorLog.
+func (log *Logger) StdLogger(level int) *log.Logger {
+	return newStdLogger(log.Writer(level))
+}
+
+func newStdLogger(out io.Writer) *log.Logger {
+	return log.New(out, "", 0)
+}
@@ -0,0 +1,104 @@
+package logbuch
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLoggerWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewStandardFormatter(""))
+	w := logger.With(Fields{"key": "value"}).Writer(LevelWarning)
+
+	if _, err := w.Write([]byte("2009/01/23 01:23:23.123123 first line\nsecond")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte(" line\r\n\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[WARN ] first line key=value\n[WARN ] second line key=value\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Expected '%v' but was: %v", expected, buffer.String())
+	}
+
+	buffer.Reset()
+	logger.SetLevel(LevelError)
+
+	if _, err := w.Write([]byte("dropped\n")); err != nil || buffer.Len() != 0 {
+		t.Fatalf("Line must have been dropped, but was: %v %v", err, buffer.String())
+	}
+}
+
+func TestLoggerWriterHeader(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewLogger(&buffer, &buffer)
+	logger.SetFormatter(NewStandardFormatter(""))
+	w := logger.Writer(LevelWarning)
+	input := []int{log.LstdFlags | log.Lshortfile, log.Lmicroseconds | log.Llongfile, log.Lshortfile, log.Ldate, 0}
+
+	for _, flags := range input {
+		log.New(w, "pfx: ", flags).Print("hello")
+		log.New(w, "", flags).Print("hello")
+	}
+
+	expected := strings.Repeat("[WARN ] hello\n", 8) + "[WARN ] pfx: hello\n[WARN ] hello\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Header must have been removed, but was: %q", buffer.String())
+	}
+
+	buffer.Reset()
+
+	for _, line := range []string{"failed to connect to db\n", "two words 2009/01/23 kept\n", "pfx: 2009/01/23 01:23:23 main.go:12: msgprefix\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected = "[WARN ] failed to connect to db\n[WARN ] two words 2009/01/23 kept\n[WARN ] msgprefix\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Message must be kept, but was: %q", buffer.String())
+	}
+}
+
+func TestLoggerStdLogger(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := NewLogger(&stdout, &stderr)
+	logger.SetFormatter(NewStandardFormatter(""))
+	l := logger.StdLogger(LevelError)
+	l.Printf("Hello %s!", "World")
+	l.Print("multiple\nlines")
+
+	if stdout.Len() != 0 || stderr.String() != "[ERROR] Hello World!\n[ERROR] multiple\n[ERROR] lines\n" {
+		t.Fatalf("Unexpected log: %v %v", stdout.String(), stderr.String())
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	SetOutput(&stdout, &stderr)
+	SetLevel(LevelDebug)
+	SetFormatter(NewStandardFormatter(""))
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("prefix: ")
+	restore := RedirectStdLog(LevelInfo)
+	log.Println("Hello World!")
+	restore()
+
+	if stdout.String() != "[INFO ] Hello World!\n" {
+		t.Fatalf("Unexpected log: %v", stdout.String())
+	}
+
+	if log.Flags() != log.LstdFlags || log.Prefix() != "prefix: " {
+		t.Fatal("Standard logger must have been restored")
+	}
+
+	log.SetPrefix("")
+}
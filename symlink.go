@@ -0,0 +1,44 @@
+package logbuch
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// CurrentSymlink makes the RollingFileAppender maintain a symbolic link with given name in the log directory,
+// pointing to the active log file. This allows tools like tail -F to follow a fixed path.
+// The link is replaced atomically on rotation. If it cannot be created,
+// the error is passed to the error handler (see OnError) and logging continues.
+// Without an error handler, the error is ignored.
+func CurrentSymlink(name string) RollingFileAppenderOption {
+	return func(appender *RollingFileAppender) {
+		appender.symlink = name
+	}
+}
+
+// updateSymlink points the symbolic link to the active log file.
+// Errors are passed to the error handler when the lock is released, as they must not stop logging.
+func (appender *RollingFileAppender) updateSymlink() {
+	if appender.symlink == "" {
+		return
+	}
+
+	link := filepath.Join(appender.fileDir, appender.symlink)
+	tmp := link + ".tmp"
+
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		appender.symlinkErrs = append(appender.symlinkErrs, err)
+		return
+	}
+
+	// the target is relative to the link, so that the log directory can be moved
+	if err := os.Symlink(filepath.Base(appender.currentFile.Name()), tmp); err != nil {
+		appender.symlinkErrs = append(appender.symlinkErrs, err)
+		return
+	}
+
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		appender.symlinkErrs = append(appender.symlinkErrs, err)
+	}
+}
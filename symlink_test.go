@@ -0,0 +1,130 @@
+package logbuch
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestRollingFileAppenderCurrentSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Creating symbolic links requires privileges on Windows")
+	}
+
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := &testMatchingNameSchema{}
+	rfa, err := NewRollingFileAppender(2, 5, 1, "out", schema, CurrentSymlink("current.log"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if target, err := os.Readlink("out/current.log"); err != nil || target != "2_log.txt" {
+		t.Fatalf("Link must point to active log file, but was: %v %v", target, err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the link must not be adopted as a log file on restart
+	rfa, err = NewRollingFileAppender(2, 5, 1, "out", schema, CurrentSymlink("current.log"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rfa.fileNames) != 2 || rfa.fileNames[0] != "out/3_log.txt" || rfa.fileNames[1] != "out/4_log.txt" {
+		t.Fatalf("Unexpected log files: %v", rfa.fileNames)
+	}
+
+	if target, err := os.Readlink("out/current.log"); err != nil || target != "4_log.txt" {
+		t.Fatalf("Link must point to active log file, but was: %v %v", target, err)
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRollingFileAppenderCurrentSymlinkError(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a non empty directory cannot be replaced by the link
+	if err := os.MkdirAll("out/current.log/dir", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	var m sync.Mutex
+	var errs []error
+	rfa, err := NewRollingFileAppender(2, 5, 1, "out", &testNameSchema{}, CurrentSymlink("current.log"), OnError(func(err error) {
+		m.Lock()
+		defer m.Unlock()
+		errs = append(errs, err)
+	}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := rfa.Write([]byte("12345\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// on creation, rotation and closing
+	if len(errs) != 3 {
+		t.Fatalf("Errors must have been reported, but was: %v", errs)
+	}
+
+	assertFileContent(t, "out/2_log.txt", "12345\n")
+	assertFileContent(t, "out/3_log.txt", "")
+}
+
+func TestRollingFileAppenderCurrentSymlinkErrorWithoutHandler(t *testing.T) {
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out/current/dir", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	rfa, err := NewRollingFileAppender(2, 100, 1, "out", &testNameSchema{}, CurrentSymlink("current"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := rfa.Write([]byte(line)); err != nil {
+			t.Fatalf("Symlink errors must not be returned, but was: %v", err)
+		}
+	}
+
+	if err := rfa.Close(); err != nil {
+		t.Fatalf("Symlink errors must not be returned, but was: %v", err)
+	}
+
+	assertFileContent(t, "out/1_log.txt", "first\nsecond\n")
+}
@@ -0,0 +1,261 @@
+package logbuch
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSyslogDialTimeout = time.Second * 5
+	syslogNilValue           = "-"
+)
+
+// SyslogFormat is the message format used by the SyslogWriter.
+type SyslogFormat int
+
+const (
+	// SyslogRFC5424 formats messages as defined in RFC 5424.
+	SyslogRFC5424 SyslogFormat = iota
+
+	// SyslogRFC3164 formats messages in the legacy BSD format as defined in RFC 3164.
+	SyslogRFC3164
+)
+
+// Facility is the syslog facility of messages written by the SyslogWriter.
+type Facility int
+
+const (
+	// FacilityUser is used for user-level messages.
+	FacilityUser Facility = iota + 1
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+)
+
+const (
+	// FacilityLocal0 to FacilityLocal7 are reserved for local use.
+	FacilityLocal0 Facility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// SyslogOptions configures the SyslogWriter.
+type SyslogOptions struct {
+	// Format is the message format. RFC 5424 is used by default.
+	Format SyslogFormat
+
+	// Facility is the facility of all messages. FacilityUser is used by default.
+	Facility Facility
+
+	// AppName is the name of the application. The name of the executable is used by default.
+	AppName string
+
+	// Hostname is the name of the host. The name reported by the operating system is used by default.
+	Hostname string
+
+	// ProcID is the process ID. The ID of the current process is used by default.
+	ProcID string
+
+	// DialTimeout is the timeout for connecting to the syslog server. Five seconds are used by default.
+	DialTimeout time.Duration
+}
+
+// SyslogWriter is an io.Writer sending log messages to a syslog server.
+// It implements the LevelWriter interface to map the level of log entries to syslog severities:
+//
+//  LevelDebug   -> debug (7)
+//  LevelInfo    -> informational (6)
+//  LevelWarning -> warning (4)
+//  LevelError   -> error (3)
+//
+// Data passed to Write is sent with severity informational. Each call sends one message.
+// If sending fails, the SyslogWriter reconnects and tries again once.
+// The SyslogWriter needs to be closed using the Close() method.
+type SyslogWriter struct {
+	network string
+	address string
+	options SyslogOptions
+	conn    net.Conn
+	buffer  []byte
+	now     func() time.Time
+	m       sync.Mutex
+}
+
+// NewSyslogWriter creates a new SyslogWriter and connects to the syslog server at given address.
+// Supported networks are "udp", "tcp" and "unixgram" (local syslog sockets like /dev/log).
+// Messages sent via TCP are framed using octet counting as defined in RFC 6587.
+//
+//  writer, err := logbuch.NewSyslogWriter("udp", "localhost:514", logbuch.SyslogOptions{AppName: "app"})
+func NewSyslogWriter(network, address string, options SyslogOptions) (*SyslogWriter, error) {
+	if network != "udp" && network != "tcp" && network != "unixgram" {
+		return nil, errors.New("network must be udp, tcp or unixgram")
+	}
+
+	if options.Facility <= 0 || options.Facility > FacilityLocal7 {
+		options.Facility = FacilityUser
+	}
+
+	if options.AppName == "" {
+		options.AppName = filepath.Base(os.Args[0])
+	}
+
+	if options.Hostname == "" {
+		options.Hostname, _ = os.Hostname()
+	}
+
+	if options.ProcID == "" {
+		options.ProcID = strconv.Itoa(os.Getpid())
+	}
+
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = defaultSyslogDialTimeout
+	}
+
+	writer := &SyslogWriter{network: network,
+		address: address,
+		options: options,
+		now:     time.Now}
+
+	if err := writer.connect(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Write sends given data as a message with severity informational.
+func (writer *SyslogWriter) Write(p []byte) (int, error) {
+	return writer.WriteLevel(LevelInfo, p)
+}
+
+// WriteLevel sends given data as a message with the severity matching the level.
+func (writer *SyslogWriter) WriteLevel(level int, p []byte) (int, error) {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+	writer.format(level, p)
+
+	if writer.conn != nil {
+		if _, err := writer.conn.Write(writer.buffer); err == nil {
+			return len(p), nil
+		}
+
+		writer.conn.Close()
+		writer.conn = nil
+	}
+
+	if err := writer.connect(); err != nil {
+		return 0, err
+	}
+
+	if _, err := writer.conn.Write(writer.buffer); err != nil {
+		writer.conn.Close()
+		writer.conn = nil
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to the syslog server.
+func (writer *SyslogWriter) Close() error {
+	writer.m.Lock()
+	defer writer.m.Unlock()
+
+	if writer.conn == nil {
+		return nil
+	}
+
+	err := writer.conn.Close()
+	writer.conn = nil
+	return err
+}
+
+func (writer *SyslogWriter) connect() error {
+	conn, err := net.DialTimeout(writer.network, writer.address, writer.options.DialTimeout)
+
+	if err != nil {
+		return err
+	}
+
+	writer.conn = conn
+	return nil
+}
+
+// format writes the message for given level and data to the buffer.
+func (writer *SyslogWriter) format(level int, p []byte) {
+	writer.buffer = append(writer.buffer[:0], '<')
+	writer.buffer = strconv.AppendInt(writer.buffer, int64(int(writer.options.Facility)*8+syslogSeverity(level)), 10)
+	writer.buffer = append(writer.buffer, '>')
+	now := writer.now()
+
+	if writer.options.Format == SyslogRFC3164 {
+		writer.buffer = now.AppendFormat(writer.buffer, time.Stamp)
+		writer.buffer = append(writer.buffer, ' ')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.Hostname)...)
+		writer.buffer = append(writer.buffer, ' ')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.AppName)...)
+		writer.buffer = append(writer.buffer, '[')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.ProcID)...)
+		writer.buffer = append(writer.buffer, "]: "...)
+	} else {
+		writer.buffer = append(writer.buffer, "1 "...)
+		writer.buffer = now.AppendFormat(writer.buffer, "2006-01-02T15:04:05.000000Z07:00")
+		writer.buffer = append(writer.buffer, ' ')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.Hostname)...)
+		writer.buffer = append(writer.buffer, ' ')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.AppName)...)
+		writer.buffer = append(writer.buffer, ' ')
+		writer.buffer = append(writer.buffer, syslogValue(writer.options.ProcID)...)
+		writer.buffer = append(writer.buffer, " - - "...)
+	}
+
+	writer.buffer = append(writer.buffer, bytes.TrimRight(p, "\r\n")...)
+
+	// octet counting framing: MSG-LEN SP SYSLOG-MSG
+	if writer.network == "tcp" {
+		frame := strconv.AppendInt(nil, int64(len(writer.buffer)), 10)
+		frame = append(frame, ' ')
+		writer.buffer = append(frame, writer.buffer...)
+	}
+}
+
+func syslogSeverity(level int) int {
+	switch level {
+	case LevelDebug:
+		return 7
+	case LevelInfo:
+		return 6
+	case LevelWarning:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// syslogValue returns the nil value for empty header fields and replaces spaces, which are used as separator.
+func syslogValue(value string) string {
+	if value == "" {
+		return syslogNilValue
+	}
+
+	return strings.ReplaceAll(value, " ", "_")
+}
@@ -0,0 +1,247 @@
+package logbuch
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var syslogTestTime = time.Date(2026, 10, 18, 9, 5, 3, 123456000, time.UTC)
+
+func TestSyslogWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	writer := newTestSyslogWriter(t, "udp", conn.LocalAddr().String(), SyslogOptions{Facility: FacilityLocal0})
+	l := NewLogger(writer, writer)
+	l.SetFormatter(NewStandardFormatter(""))
+	l.Warn("Hello %s", "World")
+	expected := "<132>1 2026-10-18T09:05:03.123456Z host app 42 - - [WARN ] Hello World"
+
+	if msg := readSyslogPacket(t, conn); msg != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, msg)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyslogWriterRFC3164(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	writer := newTestSyslogWriter(t, "udp", conn.LocalAddr().String(), SyslogOptions{Format: SyslogRFC3164, Facility: FacilityDaemon})
+
+	if _, err := writer.WriteLevel(LevelDebug, []byte("message\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<31>Oct 18 09:05:03 host app[42]: message"
+
+	if msg := readSyslogPacket(t, conn); msg != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, msg)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyslogWriterUnixgram(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix datagram sockets are not supported on Windows")
+	}
+
+	if err := os.RemoveAll("out"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll("out", 0774); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := filepath.Abs("out/syslog.sock")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.ListenPacket("unixgram", path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	writer := newTestSyslogWriter(t, "unixgram", path, SyslogOptions{})
+
+	if _, err := writer.WriteLevel(LevelError, []byte("message\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<11>1 2026-10-18T09:05:03.123456Z host app 42 - - message"
+
+	if msg := readSyslogPacket(t, conn); msg != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, msg)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyslogWriterTCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+	messages := make(chan string, 10)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go func() {
+				reader := bufio.NewReader(conn)
+
+				for {
+					frame, err := readSyslogFrame(reader)
+
+					if err != nil {
+						conn.Close()
+						return
+					}
+
+					messages <- frame
+
+					// drop the connection after the first message to force a reconnect
+					if strings.HasSuffix(frame, "first") {
+						conn.Close()
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	writer := newTestSyslogWriter(t, "tcp", listener.Addr().String(), SyslogOptions{})
+
+	if _, err := writer.WriteLevel(LevelInfo, []byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<14>1 2026-10-18T09:05:03.123456Z host app 42 - - first"
+
+	if msg := <-messages; msg != expected {
+		t.Fatalf("Expected %q, but was: %q", expected, msg)
+	}
+
+	// the first write after the connection has been closed by the server might still succeed
+	deadline := time.Now().Add(time.Second * 5)
+
+	for {
+		if _, err := writer.WriteLevel(LevelInfo, []byte("second")); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case msg := <-messages:
+			if !strings.HasSuffix(msg, "second") {
+				t.Fatalf("Unexpected message: %q", msg)
+			}
+
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			return
+		case <-time.After(time.Millisecond * 50):
+			if time.Now().After(deadline) {
+				t.Fatal("Writer must have reconnected")
+			}
+		}
+	}
+}
+
+func TestNewSyslogWriterInvalidNetwork(t *testing.T) {
+	if _, err := NewSyslogWriter("ip", "localhost", SyslogOptions{}); err == nil {
+		t.Fatal("Invalid network must be rejected")
+	}
+}
+
+func newTestSyslogWriter(t *testing.T, network, address string, options SyslogOptions) *SyslogWriter {
+	options.AppName = "app"
+	options.Hostname = "host"
+	options.ProcID = "42"
+	writer, err := NewSyslogWriter(network, address, options)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer.now = func() time.Time {
+		return syslogTestTime
+	}
+
+	return writer
+}
+
+func readSyslogPacket(t *testing.T, conn net.PacketConn) string {
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second * 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buffer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(buffer[:n])
+}
+
+func readSyslogFrame(reader *bufio.Reader) (string, error) {
+	length, err := reader.ReadString(' ')
+
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, n)
+
+	if _, err := io.ReadFull(reader, msg); err != nil {
+		return "", err
+	}
+
+	return string(msg), nil
+}